@@ -2,18 +2,25 @@ package appx
 
 import (
 	"context"
+	"io"
 	"os"
 	"os/signal"
 	"sync"
 	"syscall"
 	"time"
 
+	"github.com/go-xuan/typex"
 	"github.com/go-xuan/utilx/errorx"
 	log "github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 
+	"github.com/go-xuan/quanx/cachex"
 	"github.com/go-xuan/quanx/configx"
 	"github.com/go-xuan/quanx/dbx"
+	"github.com/go-xuan/quanx/elasticx"
+	"github.com/go-xuan/quanx/mongox"
+	"github.com/go-xuan/quanx/nacosx"
+	"github.com/go-xuan/quanx/ossx"
 	"github.com/go-xuan/quanx/serverx"
 )
 
@@ -24,20 +31,14 @@ const (
 )
 
 var (
-	engine *Engine   // 服务启动器
+	engine *Engine   // 全局Engine
 	once   sync.Once // 单例模式
 )
 
-// NewEngine 初始化Engine
+// NewEngine 初始化全局Engine，全局Engine使用各个包默认的客户端池以及默认的配置监听集合，多次调用返回同一个Engine
 func NewEngine(options ...Option) *Engine {
 	once.Do(func() {
-		engine = &Engine{
-			config:        &Config{},
-			configurators: make([]configx.Configurator, 0),
-			tablers:       make(map[string][]any),
-			servers:       make([]serverx.Server, 0),
-			flags:         make(map[string]bool),
-		}
+		engine = newEngine(true)
 	})
 	// 添加配置选项
 	for _, option := range options {
@@ -46,6 +47,44 @@ func NewEngine(options ...Option) *Engine {
 	return engine
 }
 
+// New 创建独立的Engine，拥有独立的配置、数据库/缓存/mongo/elastic/oss客户端池、配置监听以及服务列表，
+// 适用于同一进程中运行多个互不影响的Engine（例如单元测试）
+// 注意：日志以及nacos客户端仍然是全局共享的
+func New(options ...Option) *Engine {
+	e := newEngine(false)
+	for _, option := range options {
+		option(e)
+	}
+	return e
+}
+
+func newEngine(global bool) *Engine {
+	e := &Engine{
+		global:        global,
+		config:        &Config{},
+		reader:        configx.NewFileReader(DefaultConfigName),
+		health:        serverx.NewHealth(),
+		errs:          make(chan error, 1),
+		configurators: make([]configx.Configurator, 0),
+		tablers:       make(map[string][]any),
+		servers:       make([]serverx.Server, 0),
+		components:    typex.NewStringEnum[*Component](),
+		hooks:         make(map[Stage][]*Hook),
+		flags:         make(map[string]bool),
+	}
+	if global {
+		e.database, e.cache = dbx.DefaultPool(), cachex.DefaultPool()
+		e.mongo, e.elastic, e.oss = mongox.Pool(), elasticx.Pool(), ossx.DefaultPool()
+		e.watchers = configx.DefaultWatchers()
+	} else {
+		e.database, e.cache = configx.NewPool[dbx.Client](), configx.NewPool[cachex.Client]()
+		e.mongo, e.elastic, e.oss = configx.NewPool[*mongox.Client](), configx.NewPool[*elasticx.Client](), configx.NewPool[ossx.Client]()
+		e.watchers = configx.NewWatchers()
+	}
+	e.addBuiltinComponents()
+	return e
+}
+
 // GetEngine 获取当前Engine
 func GetEngine() *Engine {
 	if engine == nil {
@@ -61,24 +100,73 @@ func GetConfig() *Config {
 
 // Engine 应用启动Engine
 type Engine struct {
-	config        *Config                  // 服务启动配置
-	configurators []configx.Configurator   // 配置器
-	tablers       map[string][]any // 初始化表结构
-	servers       []serverx.Server         // http/grpc或者其他服务
-	flags         map[string]bool          // 标识
+	global        bool                            // 是否全局Engine
+	config        *Config                         // 服务启动配置
+	reader        configx.Reader                  // 服务启动配置读取器
+	args          []string                        // 命令行参数，用于覆盖服务启动配置，例如 --server.port.http=9000
+	database      *configx.Pool[dbx.Client]       // 数据库客户端池
+	cache         *configx.Pool[cachex.Client]    // 缓存客户端池
+	mongo         *configx.Pool[*mongox.Client]   // mongo客户端池
+	elastic       *configx.Pool[*elasticx.Client] // elastic客户端池
+	oss           *configx.Pool[ossx.Client]      // oss客户端池
+	watchers      *configx.Watchers               // 配置监听
+	center        serverx.Center                  // 服务注册中心
+	health        *serverx.Health                 // 健康检查
+	errs          chan error                      // 服务运行异常通道
+	configurators []configx.Configurator          // 配置器
+	tablers       map[string][]any                // 初始化表结构
+	servers       []serverx.Server                // http/grpc或者其他服务
+	components    *typex.Enum[string, *Component] // 组件
+	started       []*Component                    // 已启动的组件，按启动顺序排列
+	hooks         map[Stage][]*Hook               // 生命周期钩子
+	flags         map[string]bool                 // 标识
+	drainDelay    time.Duration                   // 置为未就绪后等待负载均衡摘除流量的时间
+	checkers      []string                        // 已注册的内置健康检查器名称，重置时注销
 }
 
-// RUN 运行应用
+// RUN 运行应用，服务运行异常时关闭服务并以非零状态码退出
 func (e *Engine) RUN(ctx context.Context) {
-	e.checkRunning()   // 检查服务是否已运行
-	e.MustInit(ctx)    // 初始化应用
-	e.startServer(ctx) // 启动服务
-	e.keepRunning(ctx) // 保持服务运行
+	e.checkRunning()          // 检查服务是否已运行
+	e.MustInit(ctx)           // 初始化应用
+	e.mustStart(ctx)          // 启动组件（包括http/grpc等服务）
+	err := e.keepRunning(ctx) // 保持服务运行
+
+	// 各组件按照自身超时时间关闭，不受外部ctx取消的影响
+	e.Shutdown(context.WithoutCancel(ctx)) // 关闭服务
+	if err != nil {
+		log.WithError(err).Error("engine exit with error")
+		os.Exit(1)
+	}
+}
+
+// GetConfig 获取当前Engine的配置
+func (e *Engine) GetConfig() *Config {
+	return e.config
+}
+
+// DatabasePool 获取当前Engine的数据库客户端池
+func (e *Engine) DatabasePool() *configx.Pool[dbx.Client] {
+	return e.database
+}
+
+// CachePool 获取当前Engine的缓存客户端池
+func (e *Engine) CachePool() *configx.Pool[cachex.Client] {
+	return e.cache
+}
+
+// MongoPool 获取当前Engine的mongo客户端池
+func (e *Engine) MongoPool() *configx.Pool[*mongox.Client] {
+	return e.mongo
+}
+
+// ElasticPool 获取当前Engine的elastic客户端池
+func (e *Engine) ElasticPool() *configx.Pool[*elasticx.Client] {
+	return e.elastic
+}
 
-	// 设定超时时间，确保服务有足够时间关闭
-	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
-	defer cancel()
-	e.Shutdown(timeoutCtx) // 关闭服务
+// OssPool 获取当前Engine的oss客户端池
+func (e *Engine) OssPool() *configx.Pool[ossx.Client] {
+	return e.oss
 }
 
 // AddServer 添加服务
@@ -91,25 +179,52 @@ func (e *Engine) MustInit(ctx context.Context) {
 	errorx.Panic(e.initOnce(ctx))
 }
 
-// LoadConfigurator 初始化自定义配置器
+// LoadConfigurator 初始化自定义配置器，内置客户端（数据库、缓存、mongo、elastic、oss）的配置器将客户端添加到当前Engine的客户端池
 func (e *Engine) LoadConfigurator(configurators ...configx.Configurator) {
 	for _, configurator := range configurators {
-		errorx.Panic(configx.LoadConfigurator(configurator))
+		errorx.Panic(e.loadConfigurator(configurator))
 	}
 }
 
 // InitTable 初始化数据库表结构以及数据
 func (e *Engine) InitTable(source string, tablers ...any) {
-	errorx.Panic(dbx.InitGormTable(dbx.GetGormDB(source), tablers...))
+	client, err := e.database.Get(source)
+	errorx.Panic(err)
+	db, ok := client.GetInstance().(*gorm.DB)
+	if !ok {
+		panic("unexpected instance type")
+	}
+	errorx.Panic(dbx.InitGormTable(db, tablers...))
 }
 
-// Shutdown 关闭服务
+// Shutdown 关闭服务，按照组件启动顺序的逆序依次关闭
+// 关闭开始时立即将服务置为未就绪，并等待 SetDrainDelay 指定的时间，使负载均衡在http服务关闭之前停止转发流量
+// 关闭后保留已添加的配置器、服务、组件以及钩子，可再次运行
 func (e *Engine) Shutdown(ctx context.Context) {
-	serverx.Shutdown(ctx, e.servers...)
+	e.health.SetReady(false)
+	e.drain(ctx)
+	e.watchers.Stop() // 停止配置监听
+	_ = e.runHooks(ctx, StageBeforeStop)
+	e.stopComponents(ctx)
+	_ = e.runHooks(ctx, StageAfterStop)
 	e.reset()
 	log.WithContext(ctx).Info("shutdown complete")
 }
 
+// 等待负载均衡通过就绪检查感知未就绪状态并摘除流量
+func (e *Engine) drain(ctx context.Context) {
+	if e.drainDelay <= 0 || !e.flags[FlagRunning] {
+		return
+	}
+	log.WithField("delay", e.drainDelay.String()).Info("waiting for traffic drain")
+	timer := time.NewTimer(e.drainDelay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
+
 // 检查服务运行状态
 func (e *Engine) checkRunning() {
 	if e.flags[FlagRunning] {
@@ -123,64 +238,152 @@ func (e *Engine) openFlag(flag string) {
 }
 
 // 应用初始化，确保必须且仅初始化一次
-func (e *Engine) initOnce(_ context.Context) error {
-	defer e.openFlag(FlagInit)
+func (e *Engine) initOnce(ctx context.Context) error {
 	if e.flags[FlagInit] {
 		return nil
 	}
+	if err := e.runHooks(ctx, StageBeforeInit); err != nil {
+		return errorx.Wrap(err, "run before init hooks failed")
+	}
+	if err := e.init(); err != nil {
+		return err
+	}
+	e.openFlag(FlagInit)
+	if err := e.runHooks(ctx, StageAfterInit); err != nil {
+		return errorx.Wrap(err, "run after init hooks failed")
+	}
+	return nil
+}
+
+// 应用初始化
+func (e *Engine) init() error {
+	// 日志中的已解析密钥脱敏
+	configx.InstallSecretHook()
 	// 初始化应用配置（日志、nacos、数据库、redis、缓存等）
-	reader := configx.NewFileReader(DefaultConfigName)
-	if err := e.config.Init(reader); err != nil {
+	if err := e.config.init(e.watchers, e.reader, e.args, e.database, e.cache); err != nil {
 		return errorx.Wrap(err, "init default config failed")
 	}
+	// 如果nacos配置启用了服务发现，则初始化服务中心，当前服务在registry组件启动时自动注册
+	if nacos := e.config.Nacos; nacos != nil && nacos.EnableNaming() {
+		e.center = serverx.NewNacosCenter(nacos.Group, nacosx.GetClient().GetNamingClient())
+		if e.global {
+			serverx.Init(e.center)
+		}
+	}
 	// 初始化配置器
 	for _, configurator := range e.configurators {
-		if err := configx.LoadConfigurator(configurator); err != nil {
+		if err := e.loadConfigurator(configurator); err != nil {
 			return errorx.Wrap(err, "load configurators failed")
 		}
 	}
+	// 注册内置客户端的健康检查
+	e.registerHealthCheckers()
 	// 初始化数据库表结构
-	if dbx.Initialized() && len(e.tablers) > 0 {
-		var err error
-		dbx.Pool().Range(func(source string, client dbx.Client) bool {
-			if tablers, ok := e.tablers[source]; ok && len(tablers) > 0 {
-				var db *gorm.DB
-				if db, ok = client.GetInstance().(*gorm.DB); ok && db != nil {
-					if err = dbx.InitGormTable(db, tablers...); err != nil {
-						err = errorx.Wrap(err, "init gorm table failed")
-						return false
-					}
+	if err := e.initTables(); err != nil {
+		return errorx.Wrap(err, "init table failed")
+	}
+	return nil
+}
+
+// 加载配置器，内置客户端的配置器将客户端添加到当前Engine的客户端池，配置监听添加到当前Engine的配置监听集合
+func (e *Engine) loadConfigurator(configurator configx.Configurator) error {
+	if configurator == nil {
+		return nil
+	}
+	execute := configurator.Execute
+	if fn, ok := executeWith(configurator, e.database); ok {
+		execute = fn
+	} else if fn, ok = executeWith(configurator, e.cache); ok {
+		execute = fn
+	} else if fn, ok = executeWith(configurator, e.mongo); ok {
+		execute = fn
+	} else if fn, ok = executeWith(configurator, e.elastic); ok {
+		execute = fn
+	} else if fn, ok = executeWith(configurator, e.oss); ok {
+		execute = fn
+	}
+	return e.watchers.LoadConfiguratorWith(configurator, execute)
+}
+
+// 配置器支持将客户端添加到指定的客户端池时，返回添加到指定客户端池的执行函数
+func executeWith[C io.Closer](configurator configx.Configurator, pool *configx.Pool[C]) (func() error, bool) {
+	if executor, ok := configurator.(interface {
+		ExecuteWith(p *configx.Pool[C]) error
+	}); ok {
+		return func() error { return executor.ExecuteWith(pool) }, true
+	}
+	return nil, false
+}
+
+// 初始化已添加的数据库表结构
+func (e *Engine) initTables() error {
+	if !e.database.Initialized() || len(e.tablers) == 0 {
+		return nil
+	}
+	var err error
+	e.database.Range(func(source string, client dbx.Client) bool {
+		if tablers, ok := e.tablers[source]; ok && len(tablers) > 0 {
+			var db *gorm.DB
+			if db, ok = client.GetInstance().(*gorm.DB); ok && db != nil {
+				if err = dbx.InitGormTable(db, tablers...); err != nil {
+					err = errorx.Wrap(err, "init gorm table failed")
+					return false
 				}
 			}
-			return true
-		})
-		if err != nil {
-			return errorx.Wrap(err, "init table failed")
 		}
+		return true
+	})
+	return err
+}
+
+// 启动应用，启动失败时关闭已启动的组件
+func (e *Engine) mustStart(ctx context.Context) {
+	if err := e.start(ctx); err != nil {
+		e.stopComponents(context.WithoutCancel(ctx))
+		panic(err)
 	}
-	return nil
 }
 
-// 启动服务
-func (e *Engine) startServer(ctx context.Context) {
-	if config := e.config.Server; config != nil {
-		errorx.Panic(serverx.Start(ctx, config, e.servers...))
+// 启动应用
+func (e *Engine) start(ctx context.Context) error {
+	if err := e.runHooks(ctx, StageBeforeStart); err != nil {
+		return errorx.Wrap(err, "run before start hooks failed")
 	}
+	if err := e.startComponents(ctx); err != nil {
+		return errorx.Wrap(err, "start components failed")
+	}
+	if err := e.runHooks(ctx, StageAfterStart); err != nil {
+		return errorx.Wrap(err, "run after start hooks failed")
+	}
+	e.health.SetReady(true)
+	return nil
 }
 
-// 保持服务运行，等待信号量关闭服务
-func (e *Engine) keepRunning(_ context.Context) {
+// 保持服务运行，直到收到退出信号、ctx被取消或者服务运行异常，服务运行异常时返回错误
+func (e *Engine) keepRunning(ctx context.Context) error {
 	e.openFlag(FlagRunning)
 
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+	defer signal.Stop(quit)
+	select {
+	case sig := <-quit:
+		log.WithField("signal", sig.String()).Info("receive quit signal")
+		return nil
+	case <-ctx.Done():
+		return nil
+	case err := <-e.errs:
+		return err
+	}
 }
 
-// 重置应用
+// 重置应用运行状态，保留已添加的配置器、表结构、服务、组件、钩子以及自定义健康检查，以便再次运行
 func (e *Engine) reset() {
-	e.configurators = make([]configx.Configurator, 0)
-	e.tablers = make(map[string][]any)
-	e.servers = make([]serverx.Server, 0)
+	for _, name := range e.checkers {
+		e.health.Deregister(name)
+	}
+	e.checkers = nil
+	e.started = nil
+	e.errs = make(chan error, 1)
 	e.flags = make(map[string]bool)
 }
@@ -0,0 +1,358 @@
+package appx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-xuan/utilx/errorx"
+	"github.com/go-xuan/utilx/filex"
+	"github.com/go-xuan/utilx/marshalx"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/go-xuan/quanx/cachex"
+	"github.com/go-xuan/quanx/configx"
+	"github.com/go-xuan/quanx/dbx"
+	"github.com/go-xuan/quanx/serverx"
+)
+
+// 命令行子命令
+const (
+	CommandServe   = "serve"   // 启动服务
+	CommandConfig  = "config"  // 配置管理（print/validate）
+	CommandMigrate = "migrate" // 初始化数据库表结构
+	CommandCheck   = "check"   // 检查全部依赖的连通性
+	CommandSchema  = "schema"  // 输出配置的JSON Schema以及示例
+	CommandHelp    = "help"    // 帮助信息
+
+	MaskedValue = configx.MaskedSecret // 敏感配置脱敏后的值
+)
+
+// 命令行参数
+const (
+	flagConfigDir = "config-dir" // 配置文件目录，默认为conf
+	flagProfile   = "profile"    // 环境，优先级高于环境变量QUANX_PROFILE
+	flagFormat    = "format"     // config print 输出格式，json/yaml，默认为yaml
+	flagOut       = "out"        // schema 输出目录
+)
+
+// 需要取值的命令行参数，支持 --key value 格式
+var valueFlags = []string{flagConfigDir, flagProfile, flagFormat, flagOut}
+
+// CLI 以命令行方式运行应用，参数取自 os.Args，执行失败时以非零状态码退出
+// 支持的子命令：serve（默认）、config print、config validate、migrate、check、schema
+func (e *Engine) CLI(ctx context.Context) {
+	if err := e.Execute(ctx, os.Args[1:]...); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+// Execute 解析命令行参数并执行对应的子命令，未指定子命令时执行serve
+func (e *Engine) Execute(ctx context.Context, args ...string) error {
+	e.args = args
+	cmd := parseCommand(args)
+	if err := cmd.apply(); err != nil {
+		return errorx.Wrap(err, "apply command flags failed")
+	}
+	switch cmd.name {
+	case "", CommandServe:
+		return e.serve(ctx)
+	case CommandConfig:
+		switch cmd.action {
+		case "print":
+			return e.printConfig(os.Stdout, cmd.flags[flagFormat])
+		case "validate":
+			return e.validateConfig(os.Stdout)
+		default:
+			return errorx.Sprintf("unknown config action: %q, available: print, validate", cmd.action)
+		}
+	case CommandMigrate:
+		return e.migrate(ctx)
+	case CommandCheck:
+		return e.check(ctx, os.Stdout)
+	case CommandSchema:
+		return e.schema(os.Stdout, cmd.action, cmd.flags[flagFormat], cmd.flags[flagOut])
+	case CommandHelp:
+		printUsage(os.Stdout)
+		return nil
+	default:
+		printUsage(os.Stderr)
+		return errorx.Sprintf("unknown command: %q", cmd.name)
+	}
+}
+
+// 命令行
+type command struct {
+	name   string            // 子命令
+	action string            // 子命令动作
+	flags  map[string]string // 参数
+}
+
+// 解析命令行参数，支持 --key=value 以及 --key value 两种格式，未知参数将被忽略
+// 仅 valueFlags 以及匹配配置字段的参数会以下一个参数作为值（布尔字段仅在下一个参数为布尔值时），
+// 其他参数视为布尔参数，避免 --verbose config print 中的子命令被误认为参数值
+func parseCommand(args []string) *command {
+	cmd := &command{flags: make(map[string]string)}
+	flags := configx.NewFlagReader()
+	var positional []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") {
+			positional = append(positional, arg)
+			continue
+		}
+		key := strings.TrimLeft(arg, "-")
+		if key == "h" || key == "help" {
+			positional = []string{CommandHelp}
+			break
+		}
+		if k, v, ok := strings.Cut(key, "="); ok {
+			cmd.flags[k] = v
+			continue
+		}
+		cmd.flags[key] = "true"
+		if i+1 >= len(args) || strings.HasPrefix(args[i+1], "-") {
+			continue
+		}
+		takesValue := slices.Contains(valueFlags, key)
+		if matched, isBool := flags.Lookup(&Config{}, key); matched {
+			_, err := strconv.ParseBool(args[i+1])
+			takesValue = !isBool || err == nil
+		}
+		if takesValue {
+			cmd.flags[key] = args[i+1]
+			i++
+		}
+	}
+	if len(positional) > 0 {
+		cmd.name = positional[0]
+	}
+	if len(positional) > 1 {
+		cmd.action = positional[1]
+	}
+	return cmd
+}
+
+// 应用命令行参数
+func (c *command) apply() error {
+	if profile := c.flags[flagProfile]; profile != "" {
+		configx.SetProfile(profile)
+	}
+	if dir := c.flags[flagConfigDir]; dir != "" {
+		if !filex.Exists(dir) {
+			return errorx.Sprintf("config dir not exist: %s", dir)
+		}
+		configx.SetFileReaderAnchor(dir)
+	}
+	return nil
+}
+
+// 打印命令行帮助信息
+func printUsage(w io.Writer) {
+	_, _ = fmt.Fprint(w, `Usage: <app> [command] [flags]
+
+Commands:
+  serve            启动服务（默认）
+  config print     打印当前生效的配置，敏感配置已脱敏
+  config validate  校验配置
+  migrate          初始化已添加的数据库表结构
+  check            检查全部依赖的连通性
+  schema [name]    输出配置的JSON Schema，--format=yaml 时输出带注释的配置示例，未指定name时列出全部配置
+
+Flags:
+  --config-dir     配置文件目录，默认为conf
+  --profile        环境，例如prod，将 database-prod.yaml 深度合并到 database.yaml 之上，默认读取环境变量QUANX_PROFILE
+  --format         config print 输出格式，json/yaml，默认为yaml
+  --out            schema 输出目录，将全部配置的 name.schema.json 以及 name.example.yaml 写入该目录
+
+Config flags（覆盖配置文件中的同名字段，例如 --server.port.http=9000 --log.level=debug）:
+`)
+	_, _ = fmt.Fprint(w, configx.NewFlagReader().Usage(&Config{}))
+}
+
+// 启动服务并保持运行，直到收到退出信号、ctx被取消或者服务运行异常
+func (e *Engine) serve(ctx context.Context) error {
+	e.checkRunning()
+	if err := e.initOnce(ctx); err != nil {
+		return errorx.Wrap(err, "init engine failed")
+	}
+	if err := e.start(ctx); err != nil {
+		e.stopComponents(context.WithoutCancel(ctx))
+		return errorx.Wrap(err, "start engine failed")
+	}
+	err := e.keepRunning(ctx)
+	e.Shutdown(context.WithoutCancel(ctx))
+	return err
+}
+
+// 读取当前生效的应用配置，仅读取配置不初始化客户端（nacos除外）
+func (e *Engine) readConfig() (*Config, error) {
+	config := &Config{}
+	if err := readAppConfig(e.reader, e.args, config); err != nil {
+		return nil, errorx.Wrap(err, "read config failed")
+	}
+	if err := config.initNacos(e.watchers); err != nil {
+		return nil, errorx.Wrap(err, "init nacos failed")
+	}
+	if err := config.initServer(e.config.Server); err != nil {
+		return nil, errorx.Wrap(err, "init server failed")
+	}
+	if config.Database == nil {
+		if dbs := (&dbx.Configs{}); readConfigurator(dbs) {
+			config.Database = dbs
+		} else if database := (&dbx.Config{}); readConfigurator(database) {
+			config.Database = &dbx.Configs{database}
+		}
+	}
+	if config.Cache == nil {
+		if caches := (&cachex.Configs{}); readConfigurator(caches) {
+			config.Cache = caches
+		} else if cache := (&cachex.Config{}); readConfigurator(cache) {
+			config.Cache = &cachex.Configs{cache}
+		}
+	}
+	return config, nil
+}
+
+// 仅读取配置器，不执行
+func readConfigurator(configurator configx.Configurator) bool {
+	_, err := configx.ReadConfigurator(configurator)
+	return err == nil
+}
+
+// 打印当前生效的配置
+func (e *Engine) printConfig(w io.Writer, format string) error {
+	config, err := e.readConfig()
+	if err != nil {
+		return err
+	}
+	masked, err := MaskSecrets(config)
+	if err != nil {
+		return errorx.Wrap(err, "mask config secrets failed")
+	}
+	if format == "" {
+		format = "yaml"
+	}
+	data, err := marshalx.Apply(format).Marshal(masked)
+	if err != nil {
+		return errorx.Wrap(err, "marshal config failed")
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// 校验配置，包括应用配置以及自定义配置器
+func (e *Engine) validateConfig(w io.Writer) error {
+	config, err := e.readConfig()
+	if err != nil {
+		return err
+	}
+	var problems []string
+	if config.Server == nil {
+		problems = append(problems, "server: config is empty")
+	} else if config.Server.Name == "" {
+		problems = append(problems, "server.name: is empty")
+	}
+	var validationErr *configx.ValidationError
+	if err = configx.Validate(config); errors.As(err, &validationErr) {
+		for _, field := range validationErr.Fields {
+			problems = append(problems, field.Error())
+		}
+	}
+	if config.Database != nil {
+		for i, database := range *config.Database {
+			if !database.Valid() {
+				problems = append(problems, fmt.Sprintf("database[%d]: dsn or host/port is required", i))
+			}
+		}
+	}
+	if config.Cache != nil {
+		for i, cache := range *config.Cache {
+			if !cache.Valid() {
+				problems = append(problems, fmt.Sprintf("cache[%d]: address is required", i))
+			}
+		}
+	}
+	for _, configurator := range e.configurators {
+		if location, err := configx.ReadConfigurator(configurator); errors.As(err, &validationErr) {
+			for _, field := range validationErr.Fields {
+				problems = append(problems, fmt.Sprintf("%T: %s", configurator, field.Error()))
+			}
+		} else if err != nil {
+			problems = append(problems, fmt.Sprintf("%T: %s (%s)", configurator, err.Error(), location))
+		}
+	}
+	if len(problems) > 0 {
+		for _, problem := range problems {
+			_, _ = fmt.Fprintln(w, "invalid", problem)
+		}
+		return errorx.Sprintf("%d invalid config item(s)", len(problems))
+	}
+	_, _ = fmt.Fprintln(w, "config is valid")
+	return nil
+}
+
+// 仅初始化数据库并执行已添加表结构的初始化
+func (e *Engine) migrate(ctx context.Context) error {
+	config, err := e.readConfig()
+	if err != nil {
+		return err
+	}
+	defer e.closeClients(ctx)
+	if err = config.initDatabase(e.watchers, e.database); err != nil {
+		return errorx.Wrap(err, "init database failed")
+	} else if !e.database.Initialized() {
+		return errorx.New("database is not configured")
+	}
+	for source := range e.tablers {
+		if _, found := e.database.Find(source); !found {
+			return errorx.Sprintf("database source not found: %s", source)
+		}
+	}
+	if err = e.initTables(); err != nil {
+		return errorx.Wrap(err, "init table failed")
+	}
+	log.WithField("sources", len(e.tablers)).Info("migrate success")
+	return nil
+}
+
+// 初始化全部依赖并执行健康检查，任一依赖异常时返回错误
+func (e *Engine) check(ctx context.Context, w io.Writer) error {
+	defer e.closeClients(ctx)
+	if err := e.initOnce(ctx); err != nil {
+		return errorx.Wrap(err, "init engine failed")
+	}
+	report := e.health.Check(ctx)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return errorx.Wrap(err, "encode health report failed")
+	}
+	if report.Status != serverx.StatusUp {
+		return errorx.New("some dependencies are unavailable")
+	}
+	return nil
+}
+
+// 关闭客户端组件，用于未启动服务的子命令
+func (e *Engine) closeClients(ctx context.Context) {
+	for _, name := range []string{ComponentDatabase, ComponentCache, ComponentMongo, ComponentElastic, ComponentOss} {
+		if component, ok := e.components.Find(name); ok && component.Stop != nil {
+			if err := runWithTimeout(ctx, component.GetTimeout(), component.Stop); err != nil {
+				log.WithField("component", name).WithError(err).Warn("close client failed")
+			}
+		}
+	}
+}
+
+// MaskSecrets 将配置转换为通用结构，并对 configx.SecretTag 标记的字段以及已解析的密钥（参考 configx.ResolveSecrets）脱敏
+func MaskSecrets(v any) (any, error) {
+	return configx.MaskFields(v)
+}
@@ -0,0 +1,67 @@
+package appx
+
+import (
+	"testing"
+
+	"github.com/go-xuan/quanx/dbx"
+	"github.com/go-xuan/quanx/ossx"
+)
+
+func TestParseCommand(t *testing.T) {
+	cmd := parseCommand([]string{"config", "print", "--config-dir=deploy", "--profile", "prod", "--unknown", "--format", "json"})
+	if cmd.name != CommandConfig || cmd.action != "print" {
+		t.Errorf("unexpected command: %s %s", cmd.name, cmd.action)
+	}
+	if cmd.flags[flagConfigDir] != "deploy" || cmd.flags[flagProfile] != "prod" || cmd.flags[flagFormat] != "json" {
+		t.Errorf("unexpected flags: %v", cmd.flags)
+	}
+	if cmd = parseCommand(nil); cmd.name != "" {
+		t.Errorf("expected default command, got %s", cmd.name)
+	}
+	if cmd = parseCommand([]string{"migrate", "-h"}); cmd.name != CommandHelp {
+		t.Errorf("expected help command, got %s", cmd.name)
+	}
+
+	// 布尔参数位于子命令之前时不会占用子命令
+	cmd = parseCommand([]string{"--verbose", "config", "print"})
+	if cmd.name != CommandConfig || cmd.action != "print" || cmd.flags["verbose"] != "true" {
+		t.Errorf("unexpected command: %s %s %v", cmd.name, cmd.action, cmd.flags)
+	}
+	// 匹配配置字段的参数取下一个参数作为值，布尔字段仅取布尔值
+	cmd = parseCommand([]string{"--log.level", "debug", "--log.color", "config", "validate"})
+	if cmd.name != CommandConfig || cmd.action != "validate" || cmd.flags["log.level"] != "debug" || cmd.flags["log.color"] != "true" {
+		t.Errorf("unexpected command: %s %s %v", cmd.name, cmd.action, cmd.flags)
+	}
+}
+
+func TestMaskSecrets(t *testing.T) {
+	config := &Config{Database: &dbx.Configs{{Source: "default", Username: "root", Password: "123456"}}}
+	masked, err := MaskSecrets(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	database := masked.(map[string]any)["database"].([]any)[0].(map[string]any)
+	if database["password"] != MaskedValue {
+		t.Errorf("password should be masked, got %v", database["password"])
+	}
+	if database["username"] != "root" {
+		t.Errorf("username should not be masked, got %v", database["username"])
+	}
+	if (*config.Database)[0].Password != "123456" {
+		t.Error("original config should not be modified")
+	}
+
+	// 仅脱敏 secret 标签标记的字段，不根据字段名称猜测
+	custom := struct {
+		Oss     *ossx.Config `json:"oss"`
+		Signing string       `json:"signing" secret:"true"`
+	}{Oss: &ossx.Config{AccessKeyId: "id", AccessKeySecret: "secret"}, Signing: "key"}
+	if masked, err = MaskSecrets(custom); err != nil {
+		t.Fatal(err)
+	}
+	result := masked.(map[string]any)
+	oss := result["oss"].(map[string]any)
+	if oss["accessKeyId"] != "id" || oss["accessKeySecret"] != MaskedValue || result["signing"] != MaskedValue {
+		t.Errorf("unexpected masked config: %v", result)
+	}
+}
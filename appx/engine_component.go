@@ -0,0 +1,223 @@
+package appx
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-xuan/utilx/errorx"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/go-xuan/quanx/serverx"
+)
+
+// 内置组件名称
+const (
+	ComponentDatabase = "database" // 数据库客户端
+	ComponentCache    = "cache"    // 缓存客户端
+	ComponentMongo    = "mongo"    // mongo客户端
+	ComponentElastic  = "elastic"  // elastic客户端
+	ComponentOss      = "oss"      // oss客户端
+	ComponentRegistry = "registry" // 服务注册
+	ComponentServer   = "server"   // http/grpc等服务
+
+	DefaultComponentTimeout = 10 * time.Second // 组件默认启动/关闭超时时间
+)
+
+// NewComponent 创建组件
+func NewComponent(name string, start, stop func(ctx context.Context) error, dependsOn ...string) *Component {
+	return &Component{
+		Name:      name,
+		DependsOn: dependsOn,
+		Start:     start,
+		Stop:      stop,
+	}
+}
+
+// Component 应用组件，由Engine按照依赖顺序启动，并按照启动顺序的逆序关闭
+type Component struct {
+	Name      string                          // 组件名称，唯一
+	DependsOn []string                        // 依赖组件，依赖组件先于当前组件启动，晚于当前组件关闭
+	Timeout   time.Duration                   // 启动/关闭超时时间，为空时使用默认超时时间
+	Start     func(ctx context.Context) error // 启动组件，可为空
+	Stop      func(ctx context.Context) error // 关闭组件，可为空
+}
+
+// GetTimeout 获取超时时间
+func (c *Component) GetTimeout() time.Duration {
+	if c.Timeout > 0 {
+		return c.Timeout
+	}
+	return DefaultComponentTimeout
+}
+
+// AddComponent 注册组件，同名组件会被覆盖
+func (e *Engine) AddComponent(components ...*Component) {
+	for _, component := range components {
+		if component != nil && component.Name != "" {
+			e.components.Add(component.Name, component)
+		}
+	}
+}
+
+// 注册内置组件，关闭顺序：服务 -> 服务注销 -> 各类客户端
+func (e *Engine) addBuiltinComponents() {
+	e.AddComponent(
+		NewComponent(ComponentDatabase, nil, closeFunc(e.database.Initialized, e.database.Close)),
+		NewComponent(ComponentCache, nil, closeFunc(e.cache.Initialized, e.cache.Close)),
+		NewComponent(ComponentMongo, nil, closeFunc(e.mongo.Initialized, e.mongo.Close)),
+		NewComponent(ComponentElastic, nil, closeFunc(e.elastic.Initialized, e.elastic.Close)),
+		NewComponent(ComponentOss, nil, closeFunc(e.oss.Initialized, e.oss.Close)),
+		NewComponent(ComponentRegistry, e.registerServer, e.deregisterServer,
+			ComponentDatabase, ComponentCache, ComponentMongo, ComponentElastic, ComponentOss),
+		NewComponent(ComponentServer, e.startServer, e.shutdownServer, ComponentRegistry),
+	)
+}
+
+// 客户端池关闭函数
+func closeFunc(initialized func() bool, close func() error) func(context.Context) error {
+	return func(context.Context) error {
+		if initialized() {
+			return close()
+		}
+		return nil
+	}
+}
+
+// 注册当前服务实例
+func (e *Engine) registerServer(_ context.Context) error {
+	if config := e.config.Server; config != nil && e.center != nil {
+		if err := serverx.ValidateInstance(config); err != nil {
+			return errorx.Wrap(err, "instance is invalid")
+		} else if err = e.center.Register(config); err != nil {
+			return errorx.Wrap(err, "register server instance failed")
+		}
+	}
+	return nil
+}
+
+// 注销当前服务实例
+func (e *Engine) deregisterServer(_ context.Context) error {
+	if config := e.config.Server; config != nil && e.center != nil {
+		if err := e.center.Deregister(config); err != nil {
+			return errorx.Wrap(err, "deregister server instance failed")
+		}
+	}
+	return nil
+}
+
+// 启动服务
+func (e *Engine) startServer(ctx context.Context) error {
+	if config := e.config.Server; config != nil {
+		for _, server := range e.servers {
+			if binder, ok := server.(serverx.HealthBinder); ok {
+				binder.BindHealth(e.health)
+			}
+			if reporter, ok := server.(serverx.ErrorReporter); ok {
+				reporter.BindErrorChan(e.errs)
+			}
+		}
+		return serverx.Start(ctx, config, e.servers...)
+	}
+	return nil
+}
+
+// 关闭服务
+func (e *Engine) shutdownServer(ctx context.Context) error {
+	serverx.Shutdown(ctx, e.servers...)
+	return nil
+}
+
+// 按照依赖顺序启动全部组件
+func (e *Engine) startComponents(ctx context.Context) error {
+	components, err := sortComponents(e.components.Values())
+	if err != nil {
+		return errorx.Wrap(err, "sort components failed")
+	}
+	for _, component := range components {
+		logger := log.WithField("component", component.Name)
+		if component.Start != nil {
+			if err = runWithTimeout(ctx, component.GetTimeout(), component.Start); err != nil {
+				logger.WithError(err).Error("start component failed")
+				return errorx.Wrap(err, "start component failed: "+component.Name)
+			}
+			logger.Debug("start component success")
+		}
+		e.started = append(e.started, component)
+	}
+	return nil
+}
+
+// 按照启动顺序的逆序关闭已启动的组件
+func (e *Engine) stopComponents(ctx context.Context) {
+	for i := len(e.started) - 1; i >= 0; i-- {
+		component := e.started[i]
+		if component.Stop == nil {
+			continue
+		}
+		logger := log.WithField("component", component.Name)
+		if err := runWithTimeout(ctx, component.GetTimeout(), component.Stop); err != nil {
+			logger.WithError(err).Error("stop component failed")
+			continue
+		}
+		logger.Debug("stop component success")
+	}
+	e.started = nil
+}
+
+// 组件拓扑排序，依赖组件排在前面，无依赖关系的组件保持注册顺序
+func sortComponents(components []*Component) ([]*Component, error) {
+	index := make(map[string]*Component, len(components))
+	for _, component := range components {
+		index[component.Name] = component
+	}
+	const (
+		visiting = 1 // 访问中
+		visited  = 2 // 已访问
+	)
+	states := make(map[string]int, len(components))
+	sorted := make([]*Component, 0, len(components))
+	var visit func(component *Component) error
+	visit = func(component *Component) error {
+		switch states[component.Name] {
+		case visited:
+			return nil
+		case visiting:
+			return errorx.Sprintf("component dependency cycle detected: %s", component.Name)
+		}
+		states[component.Name] = visiting
+		for _, name := range component.DependsOn {
+			dependency, ok := index[name]
+			if !ok {
+				return errorx.Sprintf("component %s depends on unregistered component %s", component.Name, name)
+			}
+			if err := visit(dependency); err != nil {
+				return err
+			}
+		}
+		states[component.Name] = visited
+		sorted = append(sorted, component)
+		return nil
+	}
+	for _, component := range components {
+		if err := visit(component); err != nil {
+			return nil, err
+		}
+	}
+	return sorted, nil
+}
+
+// 在超时时间内执行函数
+func runWithTimeout(ctx context.Context, timeout time.Duration, fn func(ctx context.Context) error) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- fn(ctx)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return errorx.Wrap(ctx.Err(), "execute timeout")
+	}
+}
@@ -0,0 +1,41 @@
+package appx
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSortComponents(t *testing.T) {
+	noop := func(context.Context) error { return nil }
+	components := []*Component{
+		NewComponent("consumer", noop, noop, "database", "cache"),
+		NewComponent("database", noop, noop),
+		NewComponent("cache", noop, noop, "database"),
+	}
+	sorted, err := sortComponents(components)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, component := range sorted {
+		names = append(names, component.Name)
+	}
+	if len(names) != 3 || names[0] != "database" || names[1] != "cache" || names[2] != "consumer" {
+		t.Errorf("unexpected order: %v", names)
+	}
+
+	// 循环依赖
+	components = []*Component{
+		NewComponent("a", noop, noop, "b"),
+		NewComponent("b", noop, noop, "a"),
+	}
+	if _, err = sortComponents(components); err == nil {
+		t.Error("expected dependency cycle error")
+	}
+
+	// 依赖未注册的组件
+	components = []*Component{NewComponent("a", noop, noop, "missing")}
+	if _, err = sortComponents(components); err == nil {
+		t.Error("expected unregistered dependency error")
+	}
+}
@@ -1,6 +1,8 @@
 package appx
 
 import (
+	"errors"
+
 	"github.com/go-xuan/utilx/errorx"
 	"github.com/go-xuan/utilx/filex"
 
@@ -14,15 +16,20 @@ import (
 
 // Config 应用配置
 type Config struct {
-	Server   *serverx.Config `json:"server" yaml:"server"`     // 服务配置
-	Log      *logx.Config    `json:"log" yaml:"log"`           // 日志配置
-	Nacos    *nacosx.Config  `json:"nacos" yaml:"nacos"`       // nacos配置
-	Database *dbx.Configs    `json:"database" yaml:"database"` // 数据源配置
-	Cache    *cachex.Configs `json:"cache" yaml:"cache"`       // 缓存配置
+	Server   *serverx.Config `json:"server" yaml:"server" comment:"服务配置"`      // 服务配置
+	Log      *logx.Config    `json:"log" yaml:"log" comment:"日志配置"`            // 日志配置
+	Nacos    *nacosx.Config  `json:"nacos" yaml:"nacos" comment:"nacos配置"`     // nacos配置
+	Database *dbx.Configs    `json:"database" yaml:"database" comment:"数据源配置"` // 数据源配置
+	Cache    *cachex.Configs `json:"cache" yaml:"cache" comment:"缓存配置"`        // 缓存配置
 }
 
-// Init 初始化配置
+// Init 初始化配置，数据库以及缓存客户端添加到默认客户端池
 func (cfg *Config) Init(reader configx.Reader) error {
+	return cfg.init(configx.DefaultWatchers(), reader, nil, dbx.DefaultPool(), cachex.DefaultPool())
+}
+
+// 初始化配置，数据库以及缓存客户端添加到指定的客户端池，配置监听添加到指定的配置监听集合
+func (cfg *Config) init(watchers *configx.Watchers, reader configx.Reader, args []string, database *configx.Pool[dbx.Client], cache *configx.Pool[cachex.Client]) error {
 	// 预制配置
 	var server *serverx.Config
 	if srv := cfg.Server; srv != nil {
@@ -45,11 +52,11 @@ func (cfg *Config) Init(reader configx.Reader) error {
 	}
 
 	// 读取配置文件
-	if err := reader.Read(cfg); err != nil {
+	if err := readAppConfig(reader, args, cfg); err != nil {
 		return errorx.Wrap(err, "read config failed")
 	}
 	// 初始化nacos
-	if err := cfg.initNacos(); err != nil {
+	if err := cfg.initNacos(watchers); err != nil {
 		return errorx.Wrap(err, "init nacos failed")
 	}
 	// 初始化服务配置
@@ -57,15 +64,15 @@ func (cfg *Config) Init(reader configx.Reader) error {
 		return errorx.Wrap(err, "init server failed")
 	}
 	// 初始化日志
-	if err := cfg.initLog(); err != nil {
+	if err := cfg.initLog(watchers); err != nil {
 		return errorx.Wrap(err, "init log failed")
 	}
 	// 初始化数据库
-	if err := cfg.initDatabase(); err != nil {
+	if err := cfg.initDatabase(watchers, database); err != nil {
 		return errorx.Wrap(err, "init database failed")
 	}
 	// 初始化缓存
-	if err := cfg.initCache(); err != nil {
+	if err := cfg.initCache(watchers, cache); err != nil {
 		return errorx.Wrap(err, "init cache failed")
 	}
 
@@ -73,9 +80,9 @@ func (cfg *Config) Init(reader configx.Reader) error {
 }
 
 // 初始化nacos
-func (cfg *Config) initNacos() error {
+func (cfg *Config) initNacos(watchers *configx.Watchers) error {
 	if nacos := cfg.Nacos; nacos != nil {
-		if err := configx.LoadConfigurator(nacos); err != nil {
+		if err := watchers.LoadConfigurator(nacos); err != nil {
 			return errorx.Wrap(err, "load nacos configurator failed")
 		}
 	}
@@ -96,21 +103,12 @@ func (cfg *Config) initServer(server *serverx.Config) error {
 
 		// 2.覆盖预制配置
 		cfg.Server.Cover(server)
-
-		// 如果nacos配置启用了服务发现，则自动注册当前服务
-		if cfg.Nacos != nil && cfg.Nacos.EnableNaming() {
-
-			serverx.Init(serverx.NewNacosCenter(cfg.Nacos.Group, nacosx.GetClient().GetNamingClient()))
-			if err := serverx.Register(cfg.Server); err != nil {
-				return errorx.Wrap(err, "register nacos server instance failed")
-			}
-		}
 	}
 	return nil
 }
 
 // 初始化日志配置
-func (cfg *Config) initLog() error {
+func (cfg *Config) initLog(watchers *configx.Watchers) error {
 	log := cfg.Log
 	if cfg.Log == nil {
 		log = logx.GetConfig()
@@ -118,7 +116,7 @@ func (cfg *Config) initLog() error {
 	if log.Name == "" && cfg.Server != nil {
 		log.Name = cfg.Server.Name
 	}
-	if err := configx.LoadConfigurator(log); err != nil {
+	if err := watchers.LoadConfigurator(log); err != nil {
 		return errorx.Wrap(err, "run log configurator failed")
 	}
 	cfg.Log = log
@@ -126,18 +124,18 @@ func (cfg *Config) initLog() error {
 }
 
 // 初始化数据库
-func (cfg *Config) initDatabase() error {
+func (cfg *Config) initDatabase(watchers *configx.Watchers, pool *configx.Pool[dbx.Client]) error {
 	// 读取数据库配置并初始化
 	dbs := cfg.Database
 	if dbs == nil {
 		dbs = &dbx.Configs{}
 	}
-	if err := configx.LoadConfigurator(dbs); err == nil {
+	if err := watchers.LoadConfiguratorWith(dbs, func() error { return dbs.ExecuteWith(pool) }); err == nil {
 		cfg.Database = dbs
 	}
-	if !dbx.Initialized() {
+	if !pool.Initialized() {
 		database := &dbx.Config{}
-		if err := configx.LoadConfigurator(database); err == nil {
+		if err := watchers.LoadConfiguratorWith(database, func() error { return database.ExecuteWith(pool) }); err == nil {
 			cfg.Database = &dbx.Configs{database}
 		}
 	}
@@ -145,19 +143,36 @@ func (cfg *Config) initDatabase() error {
 }
 
 // 初始化缓存
-func (cfg *Config) initCache() error {
+func (cfg *Config) initCache(watchers *configx.Watchers, pool *configx.Pool[cachex.Client]) error {
 	caches := cfg.Cache
 	if caches == nil {
 		caches = &cachex.Configs{}
 	}
-	if err := configx.LoadConfigurator(caches); err == nil {
+	if err := watchers.LoadConfiguratorWith(caches, func() error { return caches.ExecuteWith(pool) }); err == nil {
 		cfg.Cache = caches
 	}
-	if !cachex.Initialized() {
+	if !pool.Initialized() {
 		cache := &cachex.Config{}
-		if err := configx.LoadConfigurator(cache); err == nil {
+		if err := watchers.LoadConfiguratorWith(cache, func() error { return cache.ExecuteWith(pool) }); err == nil {
 			cfg.Cache = &cachex.Configs{cache}
 		}
 	}
 	return nil
 }
+
+// 读取应用配置，并依次使用 QUANX_ 前缀的环境变量（例如 QUANX_DATABASE_0_HOST）
+// 以及命令行参数（例如 --server.port.http=9000）覆盖同名字段
+func readAppConfig(reader configx.Reader, args []string, cfg *Config) error {
+	if err := configx.ReaderRead(reader, cfg); err != nil {
+		return err
+	}
+	if err := configx.NewEnvReader(configx.EnvPrefix).Read(cfg); err != nil && !errors.Is(err, configx.ErrEnvNotMatched) {
+		return errorx.Wrap(err, "read env failed")
+	}
+	if len(args) > 0 {
+		if err := configx.NewFlagReader(args...).Read(cfg); err != nil && !errors.Is(err, configx.ErrFlagNotMatched) {
+			return errorx.Wrap(err, "read flag failed")
+		}
+	}
+	return configx.ResolveSecrets(cfg)
+}
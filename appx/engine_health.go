@@ -0,0 +1,50 @@
+package appx
+
+import (
+	"io"
+
+	"github.com/go-xuan/quanx/configx"
+	"github.com/go-xuan/quanx/nacosx"
+	"github.com/go-xuan/quanx/serverx"
+)
+
+// Health 获取当前Engine的健康检查注册中心
+func (e *Engine) Health() *serverx.Health {
+	return e.health
+}
+
+// AddHealthChecker 添加自定义健康检查器，同名检查器会被覆盖
+func (e *Engine) AddHealthChecker(name string, checker serverx.HealthChecker) {
+	e.health.Register(name, checker)
+}
+
+// 注册内置客户端的健康检查，检查器名称格式为"组件名称.数据源"
+// nacos客户端为全局共享，仅由全局Engine负责检查
+func (e *Engine) registerHealthCheckers() {
+	registerPoolChecker(e, ComponentDatabase, e.database)
+	registerPoolChecker(e, ComponentCache, e.cache)
+	registerPoolChecker(e, ComponentMongo, e.mongo)
+	registerPoolChecker(e, ComponentElastic, e.elastic)
+	registerPoolChecker(e, ComponentOss, e.oss)
+	if e.global && nacosx.Initialized() {
+		e.registerHealthChecker("nacos", nacosx.GetClient())
+	}
+}
+
+// 注册内置健康检查器，并记录名称以便重置时注销
+func (e *Engine) registerHealthChecker(name string, checker serverx.HealthChecker) {
+	e.health.Register(name, checker)
+	e.checkers = append(e.checkers, name)
+}
+
+// 注册客户端池中全部客户端的健康检查，忽略default别名
+func registerPoolChecker[C interface {
+	io.Closer
+	serverx.HealthChecker
+}](e *Engine, component string, pool *configx.Pool[C]) {
+	for _, source := range pool.Sources() {
+		if client, ok := pool.Find(source); ok {
+			e.registerHealthChecker(component+"."+source, client)
+		}
+	}
+}
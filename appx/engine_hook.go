@@ -0,0 +1,70 @@
+package appx
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-xuan/utilx/errorx"
+	log "github.com/sirupsen/logrus"
+)
+
+// Stage 生命周期阶段
+type Stage string
+
+// 生命周期阶段
+const (
+	StageBeforeInit  Stage = "beforeInit"  // 初始化配置之前
+	StageAfterInit   Stage = "afterInit"   // 初始化配置（包括数据库表结构）之后
+	StageBeforeStart Stage = "beforeStart" // 启动组件之前
+	StageAfterStart  Stage = "afterStart"  // 启动组件之后
+	StageBeforeStop  Stage = "beforeStop"  // 关闭组件之前
+	StageAfterStop   Stage = "afterStop"   // 关闭组件之后
+
+	DefaultHookTimeout = 10 * time.Second // 钩子默认超时时间
+)
+
+// NewHook 创建生命周期钩子
+func NewHook(name string, fn func(ctx context.Context) error) *Hook {
+	return &Hook{Name: name, Fn: fn}
+}
+
+// Hook 生命周期钩子
+type Hook struct {
+	Name    string                          // 钩子名称
+	Timeout time.Duration                   // 超时时间，为空时使用默认超时时间
+	Fn      func(ctx context.Context) error // 钩子函数
+}
+
+// GetTimeout 获取超时时间
+func (h *Hook) GetTimeout() time.Duration {
+	if h.Timeout > 0 {
+		return h.Timeout
+	}
+	return DefaultHookTimeout
+}
+
+// AddHook 添加生命周期钩子，同一阶段的钩子按照添加顺序执行
+func (e *Engine) AddHook(stage Stage, hooks ...*Hook) {
+	for _, hook := range hooks {
+		if hook != nil && hook.Fn != nil {
+			e.hooks[stage] = append(e.hooks[stage], hook)
+		}
+	}
+}
+
+// 执行生命周期钩子
+// 启动阶段的钩子执行失败会中断启动流程，关闭阶段的钩子执行失败仅记录日志，不影响后续关闭流程
+func (e *Engine) runHooks(ctx context.Context, stage Stage) error {
+	for _, hook := range e.hooks[stage] {
+		logger := log.WithField("stage", stage).WithField("hook", hook.Name)
+		if err := runWithTimeout(ctx, hook.GetTimeout(), hook.Fn); err != nil {
+			logger.WithError(err).Error("run hook failed")
+			if stage == StageBeforeStop || stage == StageAfterStop {
+				continue
+			}
+			return errorx.Wrap(err, "run hook failed: "+hook.Name)
+		}
+		logger.Debug("run hook success")
+	}
+	return nil
+}
@@ -1,6 +1,9 @@
 package appx
 
 import (
+	"context"
+	"time"
+
 	"github.com/go-xuan/quanx/configx"
 	"github.com/go-xuan/quanx/serverx"
 )
@@ -15,6 +18,23 @@ func SetServerConfig(server *serverx.Config) Option {
 	}
 }
 
+// SetConfigReader 设置服务启动配置读取器，默认读取 conf/config.yaml
+func SetConfigReader(reader configx.Reader) Option {
+	return func(e *Engine) {
+		if reader != nil {
+			e.reader = reader
+		}
+	}
+}
+
+// SetDrainDelay 设置关闭服务时的等待时间，服务置为未就绪后等待负载均衡摘除流量，再关闭http等服务，默认不等待
+// 等待时间通常应大于负载均衡就绪检查的间隔乘以失败阈值
+func SetDrainDelay(delay time.Duration) Option {
+	return func(e *Engine) {
+		e.drainDelay = delay
+	}
+}
+
 // AddConfigurator 添加自定义配置器
 func AddConfigurator(configurators ...configx.Configurator) Option {
 	return func(e *Engine) {
@@ -40,3 +60,54 @@ func AddServer(servers ...serverx.Server) Option {
 		e.AddServer(servers...)
 	}
 }
+
+// AddComponent 添加组件
+func AddComponent(components ...*Component) Option {
+	return func(e *Engine) {
+		e.AddComponent(components...)
+	}
+}
+
+// AddHook 添加生命周期钩子
+func AddHook(stage Stage, hooks ...*Hook) Option {
+	return func(e *Engine) {
+		e.AddHook(stage, hooks...)
+	}
+}
+
+// BeforeInit 添加初始化配置之前执行的钩子
+func BeforeInit(name string, fn func(ctx context.Context) error) Option {
+	return AddHook(StageBeforeInit, NewHook(name, fn))
+}
+
+// AfterInit 添加初始化配置之后执行的钩子，此时数据库、缓存等客户端均已初始化，适用于预热缓存等场景
+func AfterInit(name string, fn func(ctx context.Context) error) Option {
+	return AddHook(StageAfterInit, NewHook(name, fn))
+}
+
+// BeforeStart 添加启动组件之前执行的钩子
+func BeforeStart(name string, fn func(ctx context.Context) error) Option {
+	return AddHook(StageBeforeStart, NewHook(name, fn))
+}
+
+// AfterStart 添加启动组件之后执行的钩子
+func AfterStart(name string, fn func(ctx context.Context) error) Option {
+	return AddHook(StageAfterStart, NewHook(name, fn))
+}
+
+// BeforeStop 添加关闭组件之前执行的钩子，适用于刷新消息队列等场景
+func BeforeStop(name string, fn func(ctx context.Context) error) Option {
+	return AddHook(StageBeforeStop, NewHook(name, fn))
+}
+
+// AfterStop 添加关闭组件之后执行的钩子
+func AfterStop(name string, fn func(ctx context.Context) error) Option {
+	return AddHook(StageAfterStop, NewHook(name, fn))
+}
+
+// AddHealthChecker 添加自定义健康检查器
+func AddHealthChecker(name string, checker serverx.HealthChecker) Option {
+	return func(e *Engine) {
+		e.AddHealthChecker(name, checker)
+	}
+}
@@ -0,0 +1,116 @@
+package appx
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-xuan/utilx/errorx"
+
+	"github.com/go-xuan/quanx/cachex"
+	"github.com/go-xuan/quanx/configx"
+	"github.com/go-xuan/quanx/dbx"
+	"github.com/go-xuan/quanx/elasticx"
+	"github.com/go-xuan/quanx/logx"
+	"github.com/go-xuan/quanx/mongox"
+	"github.com/go-xuan/quanx/nacosx"
+	"github.com/go-xuan/quanx/ossx"
+)
+
+// 配置结构，用于生成JSON Schema以及配置示例
+type schemaTarget struct {
+	name   string // 配置文件名称
+	values []any  // 配置结构，存在多种写法时单个配置在前，例如 dbx.Config、dbx.Configs
+}
+
+// 全部配置结构，包括内置配置以及自定义配置器
+func (e *Engine) schemaTargets() []schemaTarget {
+	targets := []schemaTarget{
+		{name: DefaultConfigName, values: []any{&Config{}}},
+		{name: configx.FileName(&logx.Config{}), values: []any{&logx.Config{}}},
+		{name: configx.FileName(&nacosx.Config{}), values: []any{&nacosx.Config{}}},
+		{name: configx.FileName(&dbx.Config{}), values: []any{&dbx.Config{}, &dbx.Configs{}}},
+		{name: configx.FileName(&cachex.Config{}), values: []any{&cachex.Config{}, &cachex.Configs{}}},
+		{name: configx.FileName(&mongox.Config{}), values: []any{&mongox.Config{}, &mongox.Configs{}}},
+		{name: configx.FileName(&elasticx.Config{}), values: []any{&elasticx.Config{}, &elasticx.Configs{}}},
+		{name: configx.FileName(&ossx.Config{}), values: []any{&ossx.Config{}, &ossx.Configs{}}},
+	}
+	for _, configurator := range e.configurators {
+		targets = append(targets, schemaTarget{name: schemaName(configurator), values: []any{configurator}})
+	}
+	return targets
+}
+
+// 自定义配置器的配置名称，优先使用读取的配置文件名称，没有文件读取器时使用类型名称，例如 *pkg.Config -> pkg_config.yaml
+// 配置名称用于拼接输出文件路径，因此仅保留文件名并替换其中的特殊字符
+func schemaName(configurator configx.Configurator) string {
+	name := filepath.Base(configx.FileName(configurator))
+	if name == "." || name == string(filepath.Separator) {
+		name = strings.ToLower(strings.ReplaceAll(strings.TrimLeft(fmt.Sprintf("%T", configurator), "*"), ".", "_")) + ".yaml"
+	}
+	return strings.Map(func(r rune) rune {
+		if r == '.' || r == '-' || r == '_' || (r >= '0' && r <= '9') || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// 输出配置的JSON Schema或者yaml示例
+// 未指定配置名称时列出全部配置，指定输出目录时将全部配置的 name.schema.json 以及 name.example.yaml 写入目录
+func (e *Engine) schema(w io.Writer, name, format, out string) error {
+	targets := e.schemaTargets()
+	if out != "" {
+		if err := os.MkdirAll(out, 0755); err != nil {
+			return errorx.Wrap(err, "create output dir failed")
+		}
+		for _, target := range targets {
+			if name != "" && !target.match(name) {
+				continue
+			}
+			base := filepath.Join(out, strings.TrimSuffix(target.name, filepath.Ext(target.name)))
+			data, err := configx.GenerateSchema(target.values...).JSON()
+			if err != nil {
+				return errorx.Wrap(err, "generate schema failed: "+target.name)
+			}
+			if err = os.WriteFile(base+".schema.json", data, 0644); err != nil {
+				return errorx.Wrap(err, "write schema failed: "+target.name)
+			}
+			if err = os.WriteFile(base+".example.yaml", configx.GenerateExample(target.values[0]), 0644); err != nil {
+				return errorx.Wrap(err, "write example failed: "+target.name)
+			}
+			_, _ = fmt.Fprintf(w, "%s -> %s.schema.json, %s.example.yaml\n", target.name, base, base)
+		}
+		return nil
+	}
+	if name == "" {
+		_, _ = fmt.Fprintln(w, "Available configs:")
+		for _, target := range targets {
+			_, _ = fmt.Fprintf(w, "  %s\n", target.name)
+		}
+		return nil
+	}
+	for _, target := range targets {
+		if !target.match(name) {
+			continue
+		}
+		if format == "yaml" {
+			_, err := w.Write(configx.GenerateExample(target.values[0]))
+			return err
+		}
+		data, err := configx.GenerateSchema(target.values...).JSON()
+		if err != nil {
+			return errorx.Wrap(err, "generate schema failed")
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	}
+	return errorx.Sprintf("unknown config: %q", name)
+}
+
+// 是否匹配配置名称，支持省略扩展名，例如 database 匹配 database.yaml
+func (t schemaTarget) match(name string) bool {
+	return t.name == name || strings.TrimSuffix(t.name, filepath.Ext(t.name)) == name
+}
@@ -0,0 +1,34 @@
+package appx
+
+import (
+	"testing"
+
+	"github.com/go-xuan/quanx/configx"
+)
+
+type schemaConfigurator struct {
+	readers []configx.Reader
+}
+
+func (c *schemaConfigurator) Readers() []configx.Reader { return c.readers }
+func (c *schemaConfigurator) Valid() bool               { return true }
+func (c *schemaConfigurator) Execute() error            { return nil }
+
+type genericConfigurator[T any] struct{ schemaConfigurator }
+
+func TestSchemaName(t *testing.T) {
+	cases := []struct {
+		configurator configx.Configurator
+		want         string
+	}{
+		{&schemaConfigurator{readers: []configx.Reader{configx.NewFileReader("custom.yaml")}}, "custom.yaml"},
+		{&schemaConfigurator{readers: []configx.Reader{configx.NewFileReader("sub/custom.yaml")}}, "custom.yaml"},
+		{&schemaConfigurator{readers: []configx.Reader{configx.NewDirReader("/etc/custom")}}, "appx_schemaconfigurator.yaml"},
+		{&genericConfigurator[int]{}, "appx_genericconfigurator_int_.yaml"},
+	}
+	for _, c := range cases {
+		if got := schemaName(c.configurator); got != c.want {
+			t.Errorf("schemaName(%T) = %q, want %q", c.configurator, got, c.want)
+		}
+	}
+}
@@ -1,12 +1,16 @@
 package appx
 
 import (
+	"context"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/go-xuan/quanx/configx"
 	"github.com/go-xuan/quanx/ginx"
+	"github.com/go-xuan/quanx/ossx"
 	"github.com/go-xuan/quanx/serverx"
 )
 
@@ -37,3 +41,113 @@ func TestEngine(t *testing.T) {
 		),
 	).RUN(t.Context())
 }
+
+func TestNewIsolatedEngine(t *testing.T) {
+	e1 := New(AddServer(serverx.NewHttpServer(ginx.HttpServer(), 8082)))
+	e2 := New()
+	if e1 == e2 || e1 == NewEngine() {
+		t.Fatal("engine should not be shared")
+	}
+	if e1.DatabasePool() == e2.DatabasePool() || e1.CachePool() == e2.CachePool() ||
+		e1.MongoPool() == e2.MongoPool() || e1.ElasticPool() == e2.ElasticPool() || e1.OssPool() == e2.OssPool() {
+		t.Error("client pools should be isolated")
+	}
+	if e1.watchers == e2.watchers || e1.watchers == configx.DefaultWatchers() {
+		t.Error("config watchers should be isolated")
+	}
+	if e1.DatabasePool() == NewEngine().DatabasePool() {
+		t.Error("client pools should be isolated from the global engine")
+	}
+	if len(e1.servers) != 1 || len(e2.servers) != 0 {
+		t.Error("servers should be isolated")
+	}
+}
+
+func TestShutdownDrain(t *testing.T) {
+	var start time.Time
+	var drained time.Duration
+	e := New(SetDrainDelay(50*time.Millisecond), BeforeStop("check", func(context.Context) error {
+		drained = time.Since(start)
+		return nil
+	}))
+	e.health.SetReady(true)
+	e.openFlag(FlagRunning)
+	health := e.Health()
+	start = time.Now()
+	e.Shutdown(context.Background())
+	if health.Ready() {
+		t.Error("engine should not be ready after shutdown")
+	}
+	if drained < 50*time.Millisecond {
+		t.Errorf("before stop hooks should run after the drain delay, got %s", drained)
+	}
+}
+
+func TestShutdownKeepRegistrations(t *testing.T) {
+	e := New(
+		AddComponent(NewComponent("custom", nil, nil)),
+		BeforeStop("custom", func(context.Context) error { return nil }),
+	)
+	e.AddServer(serverx.NewPprofServer())
+	e.AddHealthChecker("custom", serverx.HealthCheckFunc(func(context.Context) error { return nil }))
+	e.registerHealthChecker(ComponentDatabase+".default", serverx.HealthCheckFunc(func(context.Context) error { return nil }))
+	e.openFlag(FlagInit)
+	e.Shutdown(context.Background())
+
+	if _, ok := e.components.Find("custom"); !ok {
+		t.Error("custom component should be kept after shutdown")
+	}
+	if _, ok := e.components.Find(ComponentServer); !ok {
+		t.Error("builtin component should be kept after shutdown")
+	}
+	if len(e.servers) != 1 || len(e.hooks[StageBeforeStop]) != 1 {
+		t.Error("servers and hooks should be kept after shutdown")
+	}
+	if e.flags[FlagInit] {
+		t.Error("init flag should be reset after shutdown")
+	}
+	var names []string
+	for _, component := range e.health.Check(context.Background()).Components {
+		names = append(names, component.Name)
+	}
+	if len(names) != 1 || names[0] != "custom" {
+		t.Errorf("only custom health checkers should be kept, got %v", names)
+	}
+}
+
+type isolatedOssClient struct {
+	ossx.Client
+	closed bool
+}
+
+func (c *isolatedOssClient) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestLoadConfiguratorIsolated(t *testing.T) {
+	ossx.RegisterClientBuilder("isolated-test", func(*ossx.Config) (ossx.Client, error) {
+		return &isolatedOssClient{}, nil
+	})
+	t.Setenv("QUANX_OSS_SOURCE", "default")
+	t.Setenv("QUANX_OSS_ENABLE", "true")
+	t.Setenv("QUANX_OSS_DRIVER", "isolated-test")
+	t.Setenv("QUANX_OSS_ENDPOINT", "localhost:9000")
+
+	e := New()
+	e.LoadConfigurator(&ossx.Config{})
+	client, ok := e.OssPool().Find("default")
+	if !ok {
+		t.Fatal("oss client should be added to the engine pool")
+	}
+	if ossx.Initialized() {
+		t.Error("oss client should not be added to the global pool")
+	}
+	if err := e.start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	e.Shutdown(context.Background())
+	if !client.(*isolatedOssClient).closed || e.OssPool().Initialized() {
+		t.Error("oss client should be closed by the engine")
+	}
+}
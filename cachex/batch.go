@@ -0,0 +1,329 @@
+package cachex
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/go-xuan/utilx/errorx"
+	"github.com/go-xuan/utilx/marshalx"
+)
+
+// 批量操作每次管道执行的最大命令数量
+const batchSize = 1000
+
+// Batch 批量操作，key均使用 Config.GetKey 添加前缀
+type Batch interface {
+	MSet(ctx context.Context, values map[string]any, expiration time.Duration) error // 批量更新缓存
+	MGetString(ctx context.Context, keys ...string) (map[string]string, error)       // 批量获取缓存，不存在的key不包含在结果中
+	DeleteMany(ctx context.Context, keys ...string) (int64, error)                   // 批量删除缓存，返回删除数量
+	DeleteByPattern(ctx context.Context, pattern string) (int64, error)              // 删除匹配通配符（*、?、[abc]）的缓存，返回删除数量
+	Pipelined(ctx context.Context, fn func(pipe Pipeline) error) error               // 管道，命令在fn返回后一次性执行
+	TxPipelined(ctx context.Context, fn func(pipe Pipeline) error) error             // 事务管道，命令在fn返回后作为事务执行
+}
+
+// Pipeline 管道，命令在管道执行后才有结果，fn返回错误或者存在序列化失败的命令时不执行任何命令
+type Pipeline interface {
+	Set(key string, value any, expiration time.Duration) *Cmd // 更新缓存
+	Get(key string) *Cmd                                      // 获取缓存，不存在时 Cmd.Err 为 ErrNil
+	Delete(key string) *Cmd                                   // 删除缓存，Cmd.Int 为删除数量
+	Expire(key string, expiration time.Duration) *Cmd         // 续期缓存，Cmd.Int 为1表示成功，0表示不存在
+	IncrBy(key string, value int64) *Cmd                      // 计数器增加指定值，Cmd.Int 为新的值
+	Len() int                                                 // 命令数量
+}
+
+// Cmd 管道命令结果
+type Cmd struct {
+	val     string
+	err     error
+	marshal marshalx.Marshal
+}
+
+// Val 命令结果
+func (c *Cmd) Val() string {
+	return c.val
+}
+
+// Err 命令错误
+func (c *Cmd) Err() error {
+	return c.err
+}
+
+// Int 整数类型的命令结果
+func (c *Cmd) Int() (int64, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	return strconv.ParseInt(c.val, 10, 64)
+}
+
+// Scan 将命令结果反序列化到value（指针）
+func (c *Cmd) Scan(value any) error {
+	if c.err != nil {
+		return c.err
+	}
+	return c.marshal.Unmarshal([]byte(c.val), value)
+}
+
+func (c *Cmd) set(val string, err error) {
+	c.val, c.err = val, err
+}
+
+// GetBatch 获取客户端的批量操作，客户端不支持时panic
+func GetBatch(source ...string) Batch {
+	batch, ok := GetClient(source...).(Batch)
+	if !ok {
+		panic("cache client does not support batch operations")
+	}
+	return batch
+}
+
+// MGet 批量获取缓存并反序列化，不存在或者反序列化失败的key不包含在结果中
+func MGet[T any](ctx context.Context, client Client, keys ...string) (map[string]T, error) {
+	batch, ok := client.(Batch)
+	if !ok {
+		return nil, errorx.New("cache client does not support batch operations")
+	}
+	values, err := batch.MGetString(ctx, keys...)
+	if err != nil {
+		return nil, err
+	}
+	marshal := marshalx.Apply(client.GetConfig().Marshal)
+	result := make(map[string]T, len(values))
+	for key, value := range values {
+		var v T
+		if err = marshal.Unmarshal([]byte(value), &v); err == nil {
+			result[key] = v
+		}
+	}
+	return result, nil
+}
+
+// 管道命令类型
+type pipelineOp int
+
+const (
+	opSet pipelineOp = iota
+	opGet
+	opDelete
+	opExpire
+	opIncrBy
+)
+
+// 管道中排队的命令
+type pipelineCmd struct {
+	*Cmd
+	op         pipelineOp
+	key        string // 添加前缀后的key
+	value      string // 序列化后的值
+	expiration time.Duration
+	increment  int64
+}
+
+// 管道命令队列，由各客户端负责执行
+type pipeline struct {
+	config  *Config
+	marshal marshalx.Marshal
+	cmds    []*pipelineCmd
+	err     error // 序列化失败的错误
+}
+
+func (p *pipeline) queue(cmd *pipelineCmd) *Cmd {
+	cmd.Cmd = &Cmd{marshal: p.marshal}
+	cmd.key = p.config.GetKey(cmd.key)
+	p.cmds = append(p.cmds, cmd)
+	return cmd.Cmd
+}
+
+func (p *pipeline) Set(key string, value any, expiration time.Duration) *Cmd {
+	bytes, err := p.marshal.Marshal(value)
+	if err != nil {
+		err = errorx.Wrap(err, "marshal value failed")
+		if p.err == nil {
+			p.err = err
+		}
+		return &Cmd{err: err, marshal: p.marshal}
+	}
+	return p.queue(&pipelineCmd{op: opSet, key: key, value: string(bytes), expiration: expiration})
+}
+
+func (p *pipeline) Get(key string) *Cmd {
+	return p.queue(&pipelineCmd{op: opGet, key: key})
+}
+
+func (p *pipeline) Delete(key string) *Cmd {
+	return p.queue(&pipelineCmd{op: opDelete, key: key})
+}
+
+func (p *pipeline) Expire(key string, expiration time.Duration) *Cmd {
+	return p.queue(&pipelineCmd{op: opExpire, key: key, expiration: expiration})
+}
+
+func (p *pipeline) IncrBy(key string, value int64) *Cmd {
+	return p.queue(&pipelineCmd{op: opIncrBy, key: key, increment: value})
+}
+
+func (p *pipeline) Len() int {
+	return len(p.cmds)
+}
+
+// 创建管道并执行，返回fn的错误、序列化错误或者首个命令错误（不包括 ErrNil）
+func runPipeline(config *Config, fn func(pipe Pipeline) error, exec func(cmds []*pipelineCmd)) error {
+	p := &pipeline{config: config, marshal: marshalx.Apply(config.Marshal)}
+	if err := fn(p); err != nil {
+		return err
+	} else if p.err != nil {
+		// 存在序列化失败的命令时不执行任何命令，保证事务管道的原子性
+		return p.err
+	}
+	if len(p.cmds) > 0 {
+		exec(p.cmds)
+	}
+	for _, cmd := range p.cmds {
+		if cmd.err != nil && !errors.Is(cmd.err, ErrNil) {
+			return cmd.err
+		}
+	}
+	return nil
+}
+
+// 布尔类型的命令结果，与redis一致使用1和0表示
+func boolString(ok bool) string {
+	if ok {
+		return "1"
+	}
+	return "0"
+}
+
+// 分批执行，每批不超过 batchSize
+func inBatches(keys []string, fn func(keys []string) error) error {
+	for start := 0; start < len(keys); start += batchSize {
+		if err := fn(keys[start:min(start+batchSize, len(keys))]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 基于管道实现 Batch.MSet
+func pipelineMSet(ctx context.Context, b Batch, values map[string]any, expiration time.Duration) error {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	return inBatches(keys, func(keys []string) error {
+		return b.Pipelined(ctx, func(pipe Pipeline) error {
+			for _, key := range keys {
+				pipe.Set(key, values[key], expiration)
+			}
+			return nil
+		})
+	})
+}
+
+// 基于管道实现 Batch.MGetString
+func pipelineMGet(ctx context.Context, b Batch, keys []string) (map[string]string, error) {
+	result := make(map[string]string, len(keys))
+	err := inBatches(keys, func(keys []string) error {
+		cmds := make([]*Cmd, len(keys))
+		if err := b.Pipelined(ctx, func(pipe Pipeline) error {
+			for i, key := range keys {
+				cmds[i] = pipe.Get(key)
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+		for i, cmd := range cmds {
+			if cmd.Err() == nil {
+				result[keys[i]] = cmd.Val()
+			}
+		}
+		return nil
+	})
+	return result, err
+}
+
+// 匹配redis风格的通配符：*匹配任意字符，?匹配单个字符，[abc]、[^a]以及[a-z]匹配字符集合，\转义
+func matchPattern(pattern, s string) bool {
+	px, sx := 0, 0
+	nextPx, nextSx := 0, 0 // 回溯位置，从最近的*继续匹配
+	for px < len(pattern) || sx < len(s) {
+		if px < len(pattern) {
+			switch c := pattern[px]; c {
+			case '*':
+				nextPx, nextSx = px, sx+1
+				px++
+				continue
+			case '?':
+				if sx < len(s) {
+					px++
+					sx++
+					continue
+				}
+			case '[':
+				if sx < len(s) {
+					if matched, width := matchClass(pattern[px:], s[sx]); matched {
+						px += width
+						sx++
+						continue
+					}
+				}
+			case '\\':
+				width := 1
+				if px+1 < len(pattern) {
+					c, width = pattern[px+1], 2
+				}
+				if sx < len(s) && s[sx] == c {
+					px += width
+					sx++
+					continue
+				}
+			default:
+				if sx < len(s) && s[sx] == c {
+					px++
+					sx++
+					continue
+				}
+			}
+		}
+		if 0 < nextSx && nextSx <= len(s) {
+			px, sx = nextPx, nextSx
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// 匹配字符集合，class以[开头，返回是否匹配以及字符集合的长度，未闭合时不匹配
+func matchClass(class string, c byte) (bool, int) {
+	i, negate, matched := 1, false, false
+	if i < len(class) && class[i] == '^' {
+		negate = true
+		i++
+	}
+	for ; i < len(class) && class[i] != ']'; i++ {
+		lo := class[i]
+		if lo == '\\' && i+1 < len(class) {
+			i++
+			lo = class[i]
+		}
+		hi := lo
+		if i+2 < len(class) && class[i+1] == '-' && class[i+2] != ']' {
+			hi = class[i+2]
+			i += 2
+			if lo > hi {
+				lo, hi = hi, lo
+			}
+		}
+		if lo <= c && c <= hi {
+			matched = true
+		}
+	}
+	if i >= len(class) {
+		return false, 0
+	}
+	return matched != negate, i + 1
+}
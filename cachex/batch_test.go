@@ -0,0 +1,133 @@
+package cachex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestBatch(t *testing.T) {
+	for _, driver := range []string{"local", "memredis", "multilevel"} {
+		t.Run(driver, func(t *testing.T) {
+			client, err := NewClient(&Config{Source: driver, Driver: driver, Remote: "memredis", Prefix: "test:", Marshal: "json"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer client.Close()
+			testBatch(t, client, client.(Batch))
+		})
+	}
+}
+
+func testBatch(t *testing.T, client Client, b Batch) {
+	ctx := context.Background()
+	values := make(map[string]any)
+	for i := 0; i < batchSize+10; i++ {
+		values[fmt.Sprintf("user:%d", i)] = map[string]int{"id": i}
+	}
+	values["order:1"] = map[string]int{"id": 1}
+	if err := b.MSet(ctx, values, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	users, err := MGet[map[string]int](ctx, client, "user:1", "user:1009", "missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 2 || users["user:1009"]["id"] != 1009 {
+		t.Fatalf("unexpected result: %v", users)
+	}
+
+	n, err := b.DeleteMany(ctx, "user:0", "user:1", "missing")
+	if err != nil || n != 2 {
+		t.Fatalf("unexpected deleted: %d %v", n, err)
+	}
+	n, err = b.DeleteByPattern(ctx, "user:*")
+	if err != nil || n != int64(batchSize+8) {
+		t.Fatalf("unexpected deleted: %d %v", n, err)
+	}
+	if !client.Exist(ctx, "order:1") || client.Exist(ctx, "user:5") {
+		t.Fatal("delete by pattern should only delete matched keys")
+	}
+
+	// 管道
+	var get, incr, missing *Cmd
+	err = b.Pipelined(ctx, func(pipe Pipeline) error {
+		pipe.Set("a", "1", 0)
+		get = pipe.Get("a")
+		incr = pipe.IncrBy("counter", 5)
+		missing = pipe.Get("missing")
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var value string
+	if err = get.Scan(&value); err != nil || value != "1" {
+		t.Fatalf("unexpected get result: %s %v", value, err)
+	}
+	if n, err = incr.Int(); err != nil || n != 5 {
+		t.Fatalf("unexpected incr result: %d %v", n, err)
+	}
+	if !errors.Is(missing.Err(), ErrNil) {
+		t.Fatalf("expected ErrNil, got %v", missing.Err())
+	}
+
+	// 事务管道，fn返回错误时不执行
+	err = b.TxPipelined(ctx, func(pipe Pipeline) error {
+		pipe.Delete("a")
+		return errors.New("abort")
+	})
+	if err == nil || !client.Exist(ctx, "a") {
+		t.Fatal("aborted pipeline should not be executed")
+	}
+	// 序列化失败时不执行任何命令
+	err = b.TxPipelined(ctx, func(pipe Pipeline) error {
+		pipe.Delete("a")
+		pipe.Set("invalid", make(chan int), 0)
+		return nil
+	})
+	if err == nil || !client.Exist(ctx, "a") {
+		t.Fatal("pipeline with marshal failure should not be executed")
+	}
+	var deleted, expired *Cmd
+	err = b.TxPipelined(ctx, func(pipe Pipeline) error {
+		deleted = pipe.Delete("a")
+		expired = pipe.Expire("counter", time.Minute)
+		return nil
+	})
+	if n, _ = deleted.Int(); err != nil || n != 1 {
+		t.Fatalf("unexpected delete result: %d %v", n, err)
+	}
+	if n, _ = expired.Int(); n != 1 {
+		t.Fatalf("unexpected expire result: %d", n)
+	}
+}
+
+func TestMatchPattern(t *testing.T) {
+	cases := []struct {
+		pattern, key string
+		matched      bool
+	}{
+		{"user:*", "user:1", true},
+		{"user:*", "order:1", false},
+		{"*:1", "user:1", true},
+		{"h?llo", "hello", true},
+		{"h?llo", "hllo", false},
+		{"h[ae]llo", "hallo", true},
+		{"h[^e]llo", "hello", false},
+		{"h[a-c]llo", "hbllo", true},
+		{"a*b*c", "axxbyyc", true},
+		{"a*b*c", "axxbyy", false},
+		{`a\*`, "a*", true},
+		{`a\*`, "ab", false},
+		{"h[ab", "ha", false},
+	}
+	for _, c := range cases {
+		if matchPattern(c.pattern, c.key) != c.matched {
+			t.Errorf("matchPattern(%q, %q) should be %v", c.pattern, c.key, c.matched)
+		}
+	}
+}
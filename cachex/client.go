@@ -5,44 +5,31 @@ import (
 	"time"
 
 	"github.com/go-xuan/quanx/configx"
-	"github.com/go-xuan/typex"
-	"github.com/go-xuan/utilx/errorx"
 	"github.com/redis/go-redis/v9"
 )
 
-var (
-	pool     = configx.NewPool[Client]()        // 客户端池
-	builders *typex.Enum[string, ClientBuilder] // 客户端构造函数池
-)
+// 客户端注册中心，默认使用本地缓存
+var registry = configx.NewRegistry[*Config, Client]("cache", "local")
 
 func init() {
-	RegisterClientBuilder("local", LocalClientBuilder) // 注册本地缓存客户端构建器
-	RegisterClientBuilder("redis", RedisClientBuilder) // 注册redis缓存客户端构建器
+	RegisterClientBuilder("local", LocalClientBuilder)           // 注册本地缓存客户端构建器
+	RegisterClientBuilder("redis", RedisClientBuilder)           // 注册redis缓存客户端构建器
+	RegisterClientBuilder("memredis", MemRedisClientBuilder)     // 注册内存redis缓存客户端构建器
+	RegisterClientBuilder("multilevel", MultiLevelClientBuilder) // 注册多级缓存客户端构建器
 }
 
 // RegisterClientBuilder 注册客户端构造函数
 func RegisterClientBuilder(name string, builder ClientBuilder) {
-	if builders == nil {
-		builders = typex.NewStringEnum[ClientBuilder]()
-	}
-	builders.Add(name, builder)
+	registry.Register(name, builder)
 }
 
-// NewClient 创建客户端
+// NewClient 创建客户端，驱动为空时使用本地缓存
 func NewClient(config *Config) (Client, error) {
-	if config.Driver == "" {
-		return LocalClientBuilder(config)
-	}
-	if builders != nil {
-		if builder, ok := builders.Find(config.Driver); ok && builder != nil {
-			return builder(config)
-		}
-	}
-	return nil, errorx.Sprintf("client driver is not registered: %s", config.Driver)
+	return registry.Build(config.Driver, config)
 }
 
 // ClientBuilder 客户端构造函数
-type ClientBuilder func(*Config) (Client, error)
+type ClientBuilder = configx.Builder[*Config, Client]
 
 // Client 缓存客户端接口
 type Client interface {
@@ -50,6 +37,8 @@ type Client interface {
 	GetConfig() *Config // 获取配置
 	Close() error       // 关闭客户端,
 
+	HealthCheck(ctx context.Context) error // 健康检查
+
 	GetKey(key string) string                                                       // 获取缓存key
 	Set(ctx context.Context, key string, value any, expiration time.Duration) error // 更新缓存
 	Get(ctx context.Context, key string, value any) bool                            // 获取缓存（指针，任意类型）
@@ -59,30 +48,32 @@ type Client interface {
 	Exist(ctx context.Context, key string) bool                                     // 是否存在缓存
 }
 
+// DefaultPool 获取默认客户端池，与 Pool 不同的是，客户端池未初始化时不会panic
+func DefaultPool() *configx.Pool[Client] {
+	return registry.Pool()
+}
+
 // Pool 获取客户端池
 func Pool() *configx.Pool[Client] {
 	if !Initialized() {
-		panic("client pool not initialized")
+		panic("cache client pool not initialized")
 	}
-	return pool
+	return registry.Pool()
 }
 
 // Initialized 是否初始化
 func Initialized() bool {
-	return pool.Initialized()
+	return registry.Pool().Initialized()
 }
 
 // AddClient 添加客户端
 func AddClient(source string, client Client) {
-	if any(client) == nil {
-		return
-	}
-	pool.Add(source, client)
+	registry.Add(source, client)
 }
 
-// GetClient 获取客户端
+// GetClient 获取客户端，未指定 source 时返回 default，数据源不存在时panic
 func GetClient(source ...string) Client {
-	return pool.Get(source...)
+	return registry.MustGet(source...)
 }
 
 // GetConfig 获取配置
@@ -104,9 +95,7 @@ func GetRedisUniversalClient(source ...string) redis.UniversalClient {
 	return GetInstance[redis.UniversalClient](source...)
 }
 
-// Close 关闭所有数据库客户端
+// Close 关闭所有缓存客户端
 func Close() error {
-	return pool.Close(func(client Client) error {
-		return client.Close()
-	})
+	return registry.Close()
 }
@@ -6,7 +6,6 @@ import (
 
 	"github.com/go-xuan/utilx/errorx"
 	"github.com/go-xuan/utilx/marshalx"
-	"github.com/patrickmn/go-cache"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -15,12 +14,12 @@ func LocalClientBuilder(config *Config) (Client, error) {
 	return NewLocalClient(config)
 }
 
-// NewLocalClient 创建本地缓存客户端
+// NewLocalClient 创建本地缓存客户端，容量、淘汰策略以及过期清理间隔参考 Config
 func NewLocalClient(config *Config) (*LocalClient, error) {
 	return &LocalClient{
 		config:  config,
 		marshal: marshalx.Apply(config.Marshal),
-		cache:   cache.New(time.Duration(-1), time.Duration(-1)),
+		cache:   NewLocalCache(config),
 	}, nil
 }
 
@@ -28,10 +27,10 @@ func NewLocalClient(config *Config) (*LocalClient, error) {
 type LocalClient struct {
 	config  *Config
 	marshal marshalx.Marshal
-	cache   *cache.Cache
+	cache   *LocalCache
 }
 
-func (c *LocalClient) GetClient() *cache.Cache {
+func (c *LocalClient) GetClient() *LocalCache {
 	return c.cache
 }
 
@@ -45,11 +44,16 @@ func (c *LocalClient) GetInstance() any {
 
 func (c *LocalClient) Close() error {
 	logger := log.WithFields(c.config.LogFields())
-	c.GetClient().Flush()
+	c.GetClient().Close()
 	logger.Info("close local cache client success")
 	return nil
 }
 
+func (c *LocalClient) HealthCheck(_ context.Context) error {
+	// 本地缓存无外部依赖，始终健康
+	return nil
+}
+
 func (c *LocalClient) GetKey(key string) string {
 	return c.GetConfig().GetKey(key)
 }
@@ -73,15 +77,12 @@ func (c *LocalClient) Get(ctx context.Context, key string, value any) bool {
 }
 
 func (c *LocalClient) GetString(_ context.Context, key string) string {
-	if result, ok := c.GetClient().Get(c.GetKey(key)); ok {
-		return result.(string)
-	}
-	return ""
+	result, _ := c.GetClient().Get(c.GetKey(key))
+	return result
 }
 
 func (c *LocalClient) Delete(_ context.Context, key string) bool {
-	c.GetClient().Delete(c.GetKey(key))
-	return true
+	return c.GetClient().Delete(c.GetKey(key))
 }
 
 func (c *LocalClient) Exist(_ context.Context, key string) bool {
@@ -90,11 +91,8 @@ func (c *LocalClient) Exist(_ context.Context, key string) bool {
 }
 
 func (c *LocalClient) Expire(_ context.Context, key string, expiration time.Duration) error {
-	key = c.GetKey(key)
-	result, ok := c.GetClient().Get(key)
-	if !ok {
+	if !c.GetClient().Expire(c.GetKey(key), expiration) {
 		return errorx.New("key not found")
 	}
-	c.GetClient().Set(key, result, expiration)
 	return nil
 }
@@ -0,0 +1,70 @@
+package cachex
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+func (c *LocalClient) MSet(ctx context.Context, values map[string]any, expiration time.Duration) error {
+	return pipelineMSet(ctx, c, values, expiration)
+}
+
+func (c *LocalClient) MGetString(ctx context.Context, keys ...string) (map[string]string, error) {
+	return pipelineMGet(ctx, c, keys)
+}
+
+func (c *LocalClient) DeleteMany(_ context.Context, keys ...string) (int64, error) {
+	var deleted int64
+	c.GetClient().atomic(func(tx *localTx) {
+		for _, key := range c.GetConfig().GetKeys(keys) {
+			if tx.delete(key) {
+				deleted++
+			}
+		}
+	})
+	return deleted, nil
+}
+
+func (c *LocalClient) DeleteByPattern(_ context.Context, pattern string) (int64, error) {
+	pattern = c.GetKey(pattern)
+	deleted := c.GetClient().DeleteFunc(func(key string) bool {
+		return matchPattern(pattern, key)
+	})
+	return int64(deleted), nil
+}
+
+// Pipelined 本地缓存的管道在同一把锁内执行，与 TxPipelined 一致
+func (c *LocalClient) Pipelined(_ context.Context, fn func(pipe Pipeline) error) error {
+	return runPipeline(c.GetConfig(), fn, c.execPipeline)
+}
+
+func (c *LocalClient) TxPipelined(_ context.Context, fn func(pipe Pipeline) error) error {
+	return runPipeline(c.GetConfig(), fn, c.execPipeline)
+}
+
+func (c *LocalClient) execPipeline(cmds []*pipelineCmd) {
+	c.GetClient().atomic(func(tx *localTx) {
+		for _, cmd := range cmds {
+			switch cmd.op {
+			case opSet:
+				tx.set(cmd.key, cmd.value, cmd.expiration)
+				cmd.set("OK", nil)
+			case opGet:
+				if value, ok := tx.get(cmd.key); ok {
+					cmd.set(value, nil)
+				} else {
+					cmd.set("", ErrNil)
+				}
+			case opDelete:
+				cmd.set(boolString(tx.delete(cmd.key)), nil)
+			case opExpire:
+				cmd.set(boolString(tx.expire(cmd.key, cmd.expiration)), nil)
+			case opIncrBy:
+				var result int64
+				err := tx.update(cmd.key, true, incrFunc(cmd.increment, &result))
+				cmd.set(strconv.FormatInt(result, 10), err)
+			}
+		}
+	})
+}
@@ -0,0 +1,81 @@
+package cachex
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+// AcquireLock 本地缓存的锁仅在当前进程内有效，用于单进程部署或者单元测试
+func (c *LocalClient) AcquireLock(_ context.Context, key, token string, ttl time.Duration, reentrant bool) (bool, error) {
+	var ok bool
+	err := c.GetClient().update(c.GetKey(key), true, func(entry *cacheEntry, created bool) (int64, error) {
+		hash, err := dataOf(entry, created, func() localHash { return localHash{} })
+		if err != nil {
+			return 0, err
+		}
+		var delta int64
+		if created {
+			hash[token] = "1"
+			delta = int64(len(token) + 1)
+		} else if count, held := hash[token]; reentrant && held {
+			n, _ := strconv.Atoi(count)
+			hash[token] = strconv.Itoa(n + 1)
+			delta = int64(len(hash[token]) - len(count))
+		} else {
+			return 0, nil
+		}
+		entry.expireAt = time.Now().Add(ttl)
+		ok = true
+		return delta, nil
+	})
+	return ok, err
+}
+
+func (c *LocalClient) ReleaseLock(_ context.Context, key, token string) (bool, error) {
+	var ok bool
+	err := c.GetClient().update(c.GetKey(key), false, func(entry *cacheEntry, _ bool) (int64, error) {
+		if entry == nil {
+			return 0, nil
+		}
+		hash, err := dataOf[localHash](entry, false, nil)
+		if err != nil {
+			return 0, err
+		}
+		count, held := hash[token]
+		if !held {
+			return 0, nil
+		}
+		ok = true
+		if n, _ := strconv.Atoi(count); n > 1 {
+			hash[token] = strconv.Itoa(n - 1)
+			return int64(len(hash[token]) - len(count)), nil
+		}
+		// 删除全部字段，空哈希表将被删除
+		var delta int64
+		for field, value := range hash {
+			delete(hash, field)
+			delta -= int64(len(field) + len(value))
+		}
+		return delta, nil
+	})
+	return ok, err
+}
+
+func (c *LocalClient) RenewLock(_ context.Context, key, token string, ttl time.Duration) (bool, error) {
+	var ok bool
+	err := c.GetClient().update(c.GetKey(key), false, func(entry *cacheEntry, _ bool) (int64, error) {
+		if entry == nil {
+			return 0, nil
+		}
+		hash, err := dataOf[localHash](entry, false, nil)
+		if err != nil {
+			return 0, err
+		}
+		if _, ok = hash[token]; ok {
+			entry.expireAt = time.Now().Add(ttl)
+		}
+		return 0, nil
+	})
+	return ok, err
+}
@@ -0,0 +1,86 @@
+package cachex
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// 本地缓存中的限流状态
+type (
+	localTokenBucket   struct{ tokens, ts float64 } // 令牌桶，ts为毫秒时间戳
+	localSlidingWindow struct{ times []int64 }      // 滑动窗口，窗口内请求的毫秒时间戳，升序
+)
+
+// 限流状态占用的内存
+const (
+	tokenBucketSize = 16
+	windowItemSize  = 8
+)
+
+func (c *LocalClient) AllowTokenBucket(_ context.Context, key string, rate float64, burst, n int64) (*RateResult, error) {
+	result := &RateResult{Limit: burst}
+	now := float64(time.Now().UnixMilli())
+	err := c.GetClient().update(c.GetKey(key), true, func(entry *cacheEntry, created bool) (int64, error) {
+		bucket, err := dataOf(entry, created, func() *localTokenBucket {
+			return &localTokenBucket{tokens: float64(burst), ts: now}
+		})
+		if err != nil {
+			return 0, err
+		}
+		if now > bucket.ts {
+			bucket.tokens = math.Min(float64(burst), bucket.tokens+(now-bucket.ts)*rate/1000)
+			bucket.ts = now
+		}
+		if bucket.tokens >= float64(n) {
+			bucket.tokens -= float64(n)
+			result.Allowed = true
+		} else {
+			result.RetryAfter = time.Duration(math.Ceil((float64(n)-bucket.tokens)*1000/rate)) * time.Millisecond
+		}
+		result.Remaining = int64(bucket.tokens)
+		entry.expireAt = time.Now().Add(time.Duration(tokenBucketTTL(rate, burst)) * time.Millisecond)
+		if created {
+			return tokenBucketSize, nil
+		}
+		return 0, nil
+	})
+	return result, err
+}
+
+func (c *LocalClient) AllowSlidingWindow(_ context.Context, key string, limit int64, window time.Duration, n int64) (*RateResult, error) {
+	result := &RateResult{Limit: limit}
+	now := time.Now().UnixMilli()
+	err := c.GetClient().update(c.GetKey(key), true, func(entry *cacheEntry, created bool) (int64, error) {
+		sw, err := dataOf(entry, created, func() *localSlidingWindow { return &localSlidingWindow{} })
+		if err != nil {
+			return 0, err
+		}
+		// 删除窗口外的请求
+		var expired int
+		for expired < len(sw.times) && sw.times[expired] <= now-window.Milliseconds() {
+			expired++
+		}
+		sw.times = sw.times[expired:]
+		delta := -int64(expired * windowItemSize)
+		count := int64(len(sw.times))
+		if count+n <= limit {
+			for i := int64(0); i < n; i++ {
+				sw.times = append(sw.times, now)
+			}
+			delta += n * windowItemSize
+			result.Allowed = true
+			result.Remaining = limit - count - n
+			entry.expireAt = time.Now().Add(window)
+			return delta, nil
+		}
+		// 等待最早的count+n-limit个请求移出窗口
+		result.Remaining = max(limit-count, 0)
+		result.RetryAfter = window
+		if index := count + n - limit - 1; index < count {
+			result.RetryAfter = time.Duration(sw.times[index]+window.Milliseconds()-now) * time.Millisecond
+		}
+		return delta, nil
+	})
+	return result, err
+}
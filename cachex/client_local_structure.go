@@ -0,0 +1,533 @@
+package cachex
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// 本地缓存中的数据结构值
+type (
+	localHash      map[string]string        // 哈希表
+	localList      struct{ items []string } // 列表
+	localSet       map[string]struct{}      // 集合
+	localSortedSet map[string]float64       // 有序集合
+)
+
+// 数据结构是否为空，为空时删除缓存
+func isEmptyData(data any) bool {
+	switch v := data.(type) {
+	case localHash:
+		return len(v) == 0
+	case *localList:
+		return len(v.items) == 0
+	case localSet:
+		return len(v) == 0
+	case localSortedSet:
+		return len(v) == 0
+	}
+	return false
+}
+
+// 获取缓存中的数据结构，新创建的缓存初始化为空数据结构，类型不匹配时返回 ErrWrongType
+func dataOf[T any](entry *cacheEntry, created bool, init func() T) (T, error) {
+	if created {
+		data := init()
+		entry.data = data
+		return data, nil
+	}
+	if data, ok := entry.data.(T); ok {
+		return data, nil
+	}
+	var zero T
+	return zero, ErrWrongType
+}
+
+func (c *LocalClient) Incr(ctx context.Context, key string) (int64, error) {
+	return c.IncrBy(ctx, key, 1)
+}
+
+func (c *LocalClient) Decr(ctx context.Context, key string) (int64, error) {
+	return c.IncrBy(ctx, key, -1)
+}
+
+func (c *LocalClient) DecrBy(ctx context.Context, key string, value int64) (int64, error) {
+	return c.IncrBy(ctx, key, -value)
+}
+
+func (c *LocalClient) IncrBy(_ context.Context, key string, value int64) (int64, error) {
+	var result int64
+	err := c.GetClient().update(c.GetKey(key), true, incrFunc(value, &result))
+	return result, err
+}
+
+// 计数器增加指定值，新的值写入result
+func incrFunc(value int64, result *int64) func(entry *cacheEntry, created bool) (int64, error) {
+	return func(entry *cacheEntry, created bool) (int64, error) {
+		if entry.data != nil {
+			return 0, ErrWrongType
+		}
+		var current int64
+		if !created {
+			var err error
+			if current, err = strconv.ParseInt(entry.value, 10, 64); err != nil {
+				return 0, ErrNotInteger
+			}
+		}
+		if (value > 0 && current > math.MaxInt64-value) || (value < 0 && current < math.MinInt64-value) {
+			return 0, ErrNotInteger
+		}
+		*result = current + value
+		old := entry.value
+		entry.value = strconv.FormatInt(*result, 10)
+		return int64(len(entry.value) - len(old)), nil
+	}
+}
+
+func (c *LocalClient) HSet(_ context.Context, key string, values map[string]string) (int64, error) {
+	var added int64
+	err := c.GetClient().update(c.GetKey(key), len(values) > 0, func(entry *cacheEntry, created bool) (int64, error) {
+		if entry == nil {
+			return 0, nil
+		}
+		hash, err := dataOf(entry, created, func() localHash { return localHash{} })
+		if err != nil {
+			return 0, err
+		}
+		var delta int64
+		for field, value := range values {
+			if old, ok := hash[field]; ok {
+				delta += int64(len(value) - len(old))
+			} else {
+				delta += int64(len(field) + len(value))
+				added++
+			}
+			hash[field] = value
+		}
+		return delta, nil
+	})
+	return added, err
+}
+
+func (c *LocalClient) HGet(_ context.Context, key, field string) (string, error) {
+	var result string
+	err := c.GetClient().update(c.GetKey(key), false, func(entry *cacheEntry, _ bool) (int64, error) {
+		if entry == nil {
+			return 0, ErrNil
+		}
+		hash, err := dataOf[localHash](entry, false, nil)
+		if err != nil {
+			return 0, err
+		}
+		var ok bool
+		if result, ok = hash[field]; !ok {
+			return 0, ErrNil
+		}
+		return 0, nil
+	})
+	return result, err
+}
+
+func (c *LocalClient) HGetAll(_ context.Context, key string) (map[string]string, error) {
+	result := make(map[string]string)
+	err := c.GetClient().update(c.GetKey(key), false, func(entry *cacheEntry, _ bool) (int64, error) {
+		if entry == nil {
+			return 0, nil
+		}
+		hash, err := dataOf[localHash](entry, false, nil)
+		if err != nil {
+			return 0, err
+		}
+		for field, value := range hash {
+			result[field] = value
+		}
+		return 0, nil
+	})
+	return result, err
+}
+
+func (c *LocalClient) HDel(_ context.Context, key string, fields ...string) (int64, error) {
+	var deleted int64
+	err := c.GetClient().update(c.GetKey(key), false, func(entry *cacheEntry, _ bool) (int64, error) {
+		if entry == nil {
+			return 0, nil
+		}
+		hash, err := dataOf[localHash](entry, false, nil)
+		if err != nil {
+			return 0, err
+		}
+		var delta int64
+		for _, field := range fields {
+			if value, ok := hash[field]; ok {
+				delete(hash, field)
+				delta -= int64(len(field) + len(value))
+				deleted++
+			}
+		}
+		return delta, nil
+	})
+	return deleted, err
+}
+
+func (c *LocalClient) HLen(_ context.Context, key string) (int64, error) {
+	var length int64
+	err := c.GetClient().update(c.GetKey(key), false, func(entry *cacheEntry, _ bool) (int64, error) {
+		if entry == nil {
+			return 0, nil
+		}
+		hash, err := dataOf[localHash](entry, false, nil)
+		length = int64(len(hash))
+		return 0, err
+	})
+	return length, err
+}
+
+func (c *LocalClient) LPush(ctx context.Context, key string, values ...string) (int64, error) {
+	return c.push(ctx, key, true, values)
+}
+
+func (c *LocalClient) RPush(ctx context.Context, key string, values ...string) (int64, error) {
+	return c.push(ctx, key, false, values)
+}
+
+func (c *LocalClient) push(_ context.Context, key string, left bool, values []string) (int64, error) {
+	var length int64
+	err := c.GetClient().update(c.GetKey(key), len(values) > 0, func(entry *cacheEntry, created bool) (int64, error) {
+		if entry == nil {
+			return 0, nil
+		}
+		list, err := dataOf(entry, created, func() *localList { return &localList{} })
+		if err != nil {
+			return 0, err
+		}
+		var delta int64
+		for _, value := range values {
+			delta += int64(len(value))
+		}
+		if left {
+			// 与redis一致，依次插入头部，最后一个值位于头部
+			items := make([]string, 0, len(values)+len(list.items))
+			for i := len(values) - 1; i >= 0; i-- {
+				items = append(items, values[i])
+			}
+			list.items = append(items, list.items...)
+		} else {
+			list.items = append(list.items, values...)
+		}
+		length = int64(len(list.items))
+		return delta, nil
+	})
+	return length, err
+}
+
+func (c *LocalClient) LPop(ctx context.Context, key string) (string, error) {
+	return c.pop(ctx, key, true)
+}
+
+func (c *LocalClient) RPop(ctx context.Context, key string) (string, error) {
+	return c.pop(ctx, key, false)
+}
+
+func (c *LocalClient) pop(_ context.Context, key string, left bool) (string, error) {
+	var result string
+	err := c.GetClient().update(c.GetKey(key), false, func(entry *cacheEntry, _ bool) (int64, error) {
+		if entry == nil {
+			return 0, ErrNil
+		}
+		list, err := dataOf[*localList](entry, false, nil)
+		if err != nil {
+			return 0, err
+		}
+		if left {
+			result, list.items = list.items[0], list.items[1:]
+		} else {
+			last := len(list.items) - 1
+			result, list.items = list.items[last], list.items[:last]
+		}
+		return -int64(len(result)), nil
+	})
+	return result, err
+}
+
+func (c *LocalClient) LRange(_ context.Context, key string, start, stop int64) ([]string, error) {
+	var result []string
+	err := c.GetClient().update(c.GetKey(key), false, func(entry *cacheEntry, _ bool) (int64, error) {
+		if entry == nil {
+			return 0, nil
+		}
+		list, err := dataOf[*localList](entry, false, nil)
+		if err != nil {
+			return 0, err
+		}
+		if from, to, ok := normalizeRange(start, stop, len(list.items)); ok {
+			result = append(result, list.items[from:to+1]...)
+		}
+		return 0, nil
+	})
+	return result, err
+}
+
+func (c *LocalClient) LLen(_ context.Context, key string) (int64, error) {
+	var length int64
+	err := c.GetClient().update(c.GetKey(key), false, func(entry *cacheEntry, _ bool) (int64, error) {
+		if entry == nil {
+			return 0, nil
+		}
+		list, err := dataOf[*localList](entry, false, nil)
+		if err == nil {
+			length = int64(len(list.items))
+		}
+		return 0, err
+	})
+	return length, err
+}
+
+func (c *LocalClient) SAdd(_ context.Context, key string, members ...string) (int64, error) {
+	var added int64
+	err := c.GetClient().update(c.GetKey(key), len(members) > 0, func(entry *cacheEntry, created bool) (int64, error) {
+		if entry == nil {
+			return 0, nil
+		}
+		set, err := dataOf(entry, created, func() localSet { return localSet{} })
+		if err != nil {
+			return 0, err
+		}
+		var delta int64
+		for _, member := range members {
+			if _, ok := set[member]; !ok {
+				set[member] = struct{}{}
+				delta += int64(len(member))
+				added++
+			}
+		}
+		return delta, nil
+	})
+	return added, err
+}
+
+func (c *LocalClient) SRem(_ context.Context, key string, members ...string) (int64, error) {
+	var removed int64
+	err := c.GetClient().update(c.GetKey(key), false, func(entry *cacheEntry, _ bool) (int64, error) {
+		if entry == nil {
+			return 0, nil
+		}
+		set, err := dataOf[localSet](entry, false, nil)
+		if err != nil {
+			return 0, err
+		}
+		var delta int64
+		for _, member := range members {
+			if _, ok := set[member]; ok {
+				delete(set, member)
+				delta -= int64(len(member))
+				removed++
+			}
+		}
+		return delta, nil
+	})
+	return removed, err
+}
+
+func (c *LocalClient) SMembers(_ context.Context, key string) ([]string, error) {
+	var result []string
+	err := c.GetClient().update(c.GetKey(key), false, func(entry *cacheEntry, _ bool) (int64, error) {
+		if entry == nil {
+			return 0, nil
+		}
+		set, err := dataOf[localSet](entry, false, nil)
+		if err != nil {
+			return 0, err
+		}
+		result = make([]string, 0, len(set))
+		for member := range set {
+			result = append(result, member)
+		}
+		return 0, nil
+	})
+	return result, err
+}
+
+func (c *LocalClient) SIsMember(_ context.Context, key, member string) (bool, error) {
+	var result bool
+	err := c.GetClient().update(c.GetKey(key), false, func(entry *cacheEntry, _ bool) (int64, error) {
+		if entry == nil {
+			return 0, nil
+		}
+		set, err := dataOf[localSet](entry, false, nil)
+		if err == nil {
+			_, result = set[member]
+		}
+		return 0, err
+	})
+	return result, err
+}
+
+func (c *LocalClient) SCard(_ context.Context, key string) (int64, error) {
+	var length int64
+	err := c.GetClient().update(c.GetKey(key), false, func(entry *cacheEntry, _ bool) (int64, error) {
+		if entry == nil {
+			return 0, nil
+		}
+		set, err := dataOf[localSet](entry, false, nil)
+		length = int64(len(set))
+		return 0, err
+	})
+	return length, err
+}
+
+// 有序集合成员分数占用的内存
+const scoreSize = 8
+
+func (c *LocalClient) ZAdd(_ context.Context, key string, members ...Z) (int64, error) {
+	var added int64
+	err := c.GetClient().update(c.GetKey(key), len(members) > 0, func(entry *cacheEntry, created bool) (int64, error) {
+		if entry == nil {
+			return 0, nil
+		}
+		zset, err := dataOf(entry, created, func() localSortedSet { return localSortedSet{} })
+		if err != nil {
+			return 0, err
+		}
+		var delta int64
+		for _, member := range members {
+			if _, ok := zset[member.Member]; !ok {
+				delta += int64(len(member.Member) + scoreSize)
+				added++
+			}
+			zset[member.Member] = member.Score
+		}
+		return delta, nil
+	})
+	return added, err
+}
+
+func (c *LocalClient) ZIncrBy(_ context.Context, key string, increment float64, member string) (float64, error) {
+	var result float64
+	err := c.GetClient().update(c.GetKey(key), true, func(entry *cacheEntry, created bool) (int64, error) {
+		zset, err := dataOf(entry, created, func() localSortedSet { return localSortedSet{} })
+		if err != nil {
+			return 0, err
+		}
+		var delta int64
+		score, ok := zset[member]
+		if !ok {
+			delta = int64(len(member) + scoreSize)
+		}
+		result = score + increment
+		zset[member] = result
+		return delta, nil
+	})
+	return result, err
+}
+
+func (c *LocalClient) ZRem(_ context.Context, key string, members ...string) (int64, error) {
+	var removed int64
+	err := c.GetClient().update(c.GetKey(key), false, func(entry *cacheEntry, _ bool) (int64, error) {
+		if entry == nil {
+			return 0, nil
+		}
+		zset, err := dataOf[localSortedSet](entry, false, nil)
+		if err != nil {
+			return 0, err
+		}
+		var delta int64
+		for _, member := range members {
+			if _, ok := zset[member]; ok {
+				delete(zset, member)
+				delta -= int64(len(member) + scoreSize)
+				removed++
+			}
+		}
+		return delta, nil
+	})
+	return removed, err
+}
+
+func (c *LocalClient) ZScore(_ context.Context, key, member string) (float64, error) {
+	var result float64
+	err := c.GetClient().update(c.GetKey(key), false, func(entry *cacheEntry, _ bool) (int64, error) {
+		if entry == nil {
+			return 0, ErrNil
+		}
+		zset, err := dataOf[localSortedSet](entry, false, nil)
+		if err != nil {
+			return 0, err
+		}
+		var ok bool
+		if result, ok = zset[member]; !ok {
+			return 0, ErrNil
+		}
+		return 0, nil
+	})
+	return result, err
+}
+
+func (c *LocalClient) ZRangeByScore(_ context.Context, key string, min, max float64) ([]Z, error) {
+	var result []Z
+	err := c.GetClient().update(c.GetKey(key), false, func(entry *cacheEntry, _ bool) (int64, error) {
+		if entry == nil {
+			return 0, nil
+		}
+		zset, err := dataOf[localSortedSet](entry, false, nil)
+		if err != nil {
+			return 0, err
+		}
+		for _, z := range zset.sorted() {
+			if z.Score >= min && z.Score <= max {
+				result = append(result, z)
+			}
+		}
+		return 0, nil
+	})
+	return result, err
+}
+
+func (c *LocalClient) ZRevRange(_ context.Context, key string, start, stop int64) ([]Z, error) {
+	var result []Z
+	err := c.GetClient().update(c.GetKey(key), false, func(entry *cacheEntry, _ bool) (int64, error) {
+		if entry == nil {
+			return 0, nil
+		}
+		zset, err := dataOf[localSortedSet](entry, false, nil)
+		if err != nil {
+			return 0, err
+		}
+		sorted := zset.sorted()
+		if from, to, ok := normalizeRange(start, stop, len(sorted)); ok {
+			for i := from; i <= to; i++ {
+				result = append(result, sorted[len(sorted)-1-i])
+			}
+		}
+		return 0, nil
+	})
+	return result, err
+}
+
+func (c *LocalClient) ZCard(_ context.Context, key string) (int64, error) {
+	var length int64
+	err := c.GetClient().update(c.GetKey(key), false, func(entry *cacheEntry, _ bool) (int64, error) {
+		if entry == nil {
+			return 0, nil
+		}
+		zset, err := dataOf[localSortedSet](entry, false, nil)
+		length = int64(len(zset))
+		return 0, err
+	})
+	return length, err
+}
+
+// 按照分数升序排列，分数相同时按照成员字典序排列
+func (s localSortedSet) sorted() []Z {
+	result := make([]Z, 0, len(s))
+	for member, score := range s {
+		result = append(result, Z{Member: member, Score: score})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Score != result[j].Score {
+			return result[i].Score < result[j].Score
+		}
+		return result[i].Member < result[j].Member
+	})
+	return result
+}
@@ -0,0 +1,92 @@
+package cachex
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-xuan/utilx/errorx"
+	log "github.com/sirupsen/logrus"
+)
+
+// 内嵌redis服务推进时间的间隔，过期时间的精度
+const memRedisTick = 10 * time.Millisecond
+
+// MemRedisClientBuilder 内存redis缓存客户端构建器
+func MemRedisClientBuilder(config *Config) (Client, error) {
+	return NewMemRedisClient(config)
+}
+
+// NewMemRedisClient 在进程内启动内嵌的redis服务并创建redis缓存客户端，用于单元测试，无需外部redis服务
+// 1. 地址为host:port格式时监听该地址，否则监听随机端口，实际地址参考 GetConfig().Address
+// 2. 支持过期时间、发布订阅以及lua脚本，过期时间按照实际时间推进
+// 3. 关闭客户端时同时关闭内嵌服务，数据不会持久化
+func NewMemRedisClient(config *Config) (*MemRedisClient, error) {
+	server := miniredis.NewMiniRedis()
+	if config.Password != "" {
+		if config.Username != "" {
+			server.RequireUserAuth(config.Username, config.Password)
+		} else {
+			server.RequireAuth(config.Password)
+		}
+	}
+	addr := "127.0.0.1:0"
+	if _, _, err := net.SplitHostPort(config.Address); err == nil {
+		addr = config.Address
+	}
+	if err := server.StartAddr(addr); err != nil {
+		return nil, errorx.Wrap(err, "start memory redis server failed")
+	}
+	conf := config.Copy()
+	conf.Address = server.Addr()
+	conf.Mode = ModeStandAlone
+	client, err := NewRedisClient(conf)
+	if err != nil {
+		server.Close()
+		return nil, errorx.Wrap(err, "create memory redis client failed")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	go tickMemRedis(ctx, server)
+	log.WithFields(conf.LogFields()).Info("memory redis server started")
+	return &MemRedisClient{
+		RedisClient: client,
+		server:      server,
+		cancel:      cancel,
+	}, nil
+}
+
+// MemRedisClient 内存redis缓存客户端，连接内嵌的redis服务，除关闭外与 RedisClient 一致
+type MemRedisClient struct {
+	*RedisClient
+	server *miniredis.Miniredis
+	cancel context.CancelFunc
+}
+
+// GetServer 获取内嵌的redis服务，可用于在测试中直接检查数据或者推进时间
+func (c *MemRedisClient) GetServer() *miniredis.Miniredis {
+	return c.server
+}
+
+func (c *MemRedisClient) Close() error {
+	c.cancel()
+	err := c.RedisClient.Close()
+	c.server.Close()
+	return err
+}
+
+// 按照实际时间推进内嵌服务的时间，使过期时间生效
+func tickMemRedis(ctx context.Context, server *miniredis.Miniredis) {
+	ticker := time.NewTicker(memRedisTick)
+	defer ticker.Stop()
+	last := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			server.FastForward(now.Sub(last))
+			last = now
+		}
+	}
+}
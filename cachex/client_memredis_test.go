@@ -0,0 +1,60 @@
+package cachex
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestMemRedisClient(t *testing.T) {
+	client, err := NewClient(&Config{Source: "test", Driver: "memredis", Prefix: "test:", Marshal: "json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	if err = client.Set(ctx, "key", map[string]int{"a": 1}, 100*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	var value map[string]int
+	if !client.Get(ctx, "key", &value) || value["a"] != 1 {
+		t.Fatalf("unexpected value: %v", value)
+	}
+	time.Sleep(200 * time.Millisecond)
+	if client.Exist(ctx, "key") {
+		t.Fatal("key should be expired")
+	}
+
+	rdb, ok := client.GetInstance().(redis.UniversalClient)
+	if !ok {
+		t.Fatal("unexpected instance type")
+	}
+
+	// 发布订阅
+	sub := rdb.Subscribe(ctx, "channel")
+	defer sub.Close()
+	if _, err = sub.Receive(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err = rdb.Publish(ctx, "channel", "hello").Err(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case msg := <-sub.Channel():
+		if msg.Payload != "hello" {
+			t.Fatalf("unexpected message: %s", msg.Payload)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("message not received")
+	}
+
+	// lua脚本
+	result, err := rdb.Eval(ctx, `redis.call("SET", KEYS[1], ARGV[1]); return redis.call("INCRBY", KEYS[1], 2)`,
+		[]string{"counter"}, 40).Int()
+	if err != nil || result != 42 {
+		t.Fatalf("unexpected eval result: %d %v", result, err)
+	}
+}
@@ -0,0 +1,402 @@
+package cachex
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-xuan/utilx/errorx"
+	"github.com/go-xuan/utilx/marshalx"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	InvalidateChannel        = "cachex:invalidate"         // 多级缓存失效广播频道
+	InvalidatePatternChannel = "cachex:invalidate:pattern" // 多级缓存按照通配符失效广播频道
+	defaultLocalSize         = 10000                       // 多级缓存的本地缓存默认最大数量
+	defaultLocalExpire       = 60                          // 本地缓存默认最长过期时间（秒）
+)
+
+// MultiLevelClientBuilder 多级缓存客户端构建器
+func MultiLevelClientBuilder(config *Config) (Client, error) {
+	return NewMultiLevelClient(config)
+}
+
+// NewMultiLevelClient 创建多级缓存客户端，一级为容量有限的本地缓存，二级为redis缓存
+// 1. 读取时优先读取本地缓存，未命中时读取redis并写入本地缓存
+// 2. 写入时同时写入redis以及本地缓存，并通过redis发布订阅广播失效消息，其他实例收到后删除本地缓存
+// 3. 本地缓存的过期时间不超过redis中的剩余过期时间以及 Config.LocalExpire
+// 4. 数据结构操作直接访问redis，计数器修改后删除本地缓存并广播失效消息
+// 5. 批量操作以及管道直接访问redis，执行后删除涉及的本地缓存并广播失效消息
+func NewMultiLevelClient(config *Config) (*MultiLevelClient, error) {
+	driver := config.Remote
+	if driver == "" {
+		driver = "redis"
+	} else if driver == "multilevel" {
+		return nil, errorx.New("multilevel cache remote driver can not be multilevel")
+	}
+	remote, err := NewClient(withDriver(config, driver))
+	if err != nil {
+		return nil, errorx.Wrap(err, "create remote cache client failed")
+	}
+	rdb, ok := remote.GetInstance().(redis.UniversalClient)
+	if !ok {
+		_ = remote.Close()
+		return nil, errorx.Sprintf("remote cache driver is not redis based: %s", driver)
+	}
+	structure, ok := remote.(Structure)
+	if !ok {
+		_ = remote.Close()
+		return nil, errorx.Sprintf("remote cache driver does not support data structures: %s", driver)
+	}
+	batch, ok := remote.(Batch)
+	if !ok {
+		_ = remote.Close()
+		return nil, errorx.Sprintf("remote cache driver does not support batch operations: %s", driver)
+	}
+	locker, ok := remote.(Locker)
+	if !ok {
+		_ = remote.Close()
+		return nil, errorx.Sprintf("remote cache driver does not support lock: %s", driver)
+	}
+	limiter, ok := remote.(RateLimitStore)
+	if !ok {
+		_ = remote.Close()
+		return nil, errorx.Sprintf("remote cache driver does not support rate limit: %s", driver)
+	}
+	local := config.Copy()
+	if local.MaxEntries <= 0 && local.MaxBytes <= 0 {
+		local.MaxEntries = defaultLocalSize
+	}
+	expire := config.LocalExpire
+	if expire <= 0 {
+		expire = defaultLocalExpire
+	}
+	client := &MultiLevelClient{
+		Structure:   structure,
+		config:      config,
+		marshal:     marshalx.Apply(config.Marshal),
+		local:       NewLocalCache(local),
+		localExpire: time.Duration(expire) * time.Second,
+		remote:      remote,
+		batch:       batch,
+		locker:      locker,
+		limiter:     limiter,
+		rdb:         rdb,
+		id:          uuid.NewString(),
+	}
+	if err = client.subscribe(); err != nil {
+		_ = remote.Close()
+		return nil, errorx.Wrap(err, "subscribe invalidate channel failed")
+	}
+	return client, nil
+}
+
+// 复制配置并指定驱动
+func withDriver(config *Config, driver string) *Config {
+	conf := config.Copy()
+	conf.Driver = driver
+	return conf
+}
+
+// MultiLevelClient 多级缓存客户端
+type MultiLevelClient struct {
+	Structure   // 数据结构操作，由二级缓存实现
+	config      *Config
+	marshal     marshalx.Marshal
+	local       *LocalCache           // 一级缓存
+	localExpire time.Duration         // 一级缓存最长过期时间
+	remote      Client                // 二级缓存
+	batch       Batch                 // 二级缓存的批量操作
+	locker      Locker                // 二级缓存的分布式锁
+	limiter     RateLimitStore        // 二级缓存的限流存储
+	rdb         redis.UniversalClient // 二级缓存的redis客户端
+	pubsub      *redis.PubSub         // 失效消息订阅
+	id          string                // 实例ID，用于忽略自身发出的失效消息
+	mu          sync.Mutex            // 本地缓存修改锁，保证失效版本检查与回填本地缓存的原子性
+	generation  atomic.Uint64         // 本地缓存失效版本，每次修改本地缓存时递增
+	stats       struct {
+		localHits, localMisses, remoteHits, remoteMisses, invalidations atomic.Uint64
+	}
+}
+
+// Stats 多级缓存命中统计
+type Stats struct {
+	LocalHits     uint64 `json:"localHits"`     // 本地缓存命中次数
+	LocalMisses   uint64 `json:"localMisses"`   // 本地缓存未命中次数
+	RemoteHits    uint64 `json:"remoteHits"`    // redis命中次数
+	RemoteMisses  uint64 `json:"remoteMisses"`  // redis未命中次数
+	Invalidations uint64 `json:"invalidations"` // 收到的失效消息数量
+	LocalSize     int    `json:"localSize"`     // 本地缓存数量
+}
+
+// LocalHitRatio 本地缓存命中率
+func (s Stats) LocalHitRatio() float64 {
+	return ratio(s.LocalHits, s.LocalMisses)
+}
+
+// RemoteHitRatio redis命中率，仅统计本地缓存未命中的请求
+func (s Stats) RemoteHitRatio() float64 {
+	return ratio(s.RemoteHits, s.RemoteMisses)
+}
+
+// HitRatio 总命中率
+func (s Stats) HitRatio() float64 {
+	return ratio(s.LocalHits+s.RemoteHits, s.RemoteMisses)
+}
+
+func ratio(hits, misses uint64) float64 {
+	if total := hits + misses; total > 0 {
+		return float64(hits) / float64(total)
+	}
+	return 0
+}
+
+// Stats 获取命中统计
+func (c *MultiLevelClient) Stats() Stats {
+	return Stats{
+		LocalHits:     c.stats.localHits.Load(),
+		LocalMisses:   c.stats.localMisses.Load(),
+		RemoteHits:    c.stats.remoteHits.Load(),
+		RemoteMisses:  c.stats.remoteMisses.Load(),
+		Invalidations: c.stats.invalidations.Load(),
+		LocalSize:     c.local.Len(),
+	}
+}
+
+// GetRemote 获取二级缓存客户端
+func (c *MultiLevelClient) GetRemote() Client {
+	return c.remote
+}
+
+func (c *MultiLevelClient) GetConfig() *Config {
+	return c.config
+}
+
+// GetInstance 获取二级缓存的redis客户端
+func (c *MultiLevelClient) GetInstance() any {
+	return c.rdb
+}
+
+func (c *MultiLevelClient) Close() error {
+	stats := c.Stats()
+	logger := log.WithFields(c.config.LogFields()).
+		WithField("local_hit_ratio", stats.LocalHitRatio()).
+		WithField("remote_hit_ratio", stats.RemoteHitRatio())
+	_ = c.pubsub.Close()
+	c.local.Close()
+	if err := c.remote.Close(); err != nil {
+		logger.WithError(err).Error("close multilevel cache client failed")
+		return errorx.Wrap(err, "close multilevel cache client failed")
+	}
+	logger.Info("close multilevel cache client success")
+	return nil
+}
+
+func (c *MultiLevelClient) HealthCheck(ctx context.Context) error {
+	return c.remote.HealthCheck(ctx)
+}
+
+func (c *MultiLevelClient) GetKey(key string) string {
+	return c.GetConfig().GetKey(key)
+}
+
+func (c *MultiLevelClient) Set(ctx context.Context, key string, value any, expiration time.Duration) error {
+	bytes, err := c.marshal.Marshal(value)
+	if err != nil {
+		return errorx.Wrap(err, "marshal value failed")
+	}
+	key = c.GetKey(key)
+	if err = c.rdb.Set(ctx, key, bytes, expiration).Err(); err != nil {
+		return errorx.Wrap(err, "set value failed")
+	}
+	c.updateLocal(func(local *LocalCache) { local.Set(key, string(bytes), c.localTTL(expiration)) })
+	c.invalidate(ctx, key)
+	return nil
+}
+
+func (c *MultiLevelClient) Get(ctx context.Context, key string, value any) bool {
+	if result := c.GetString(ctx, key); result != "" {
+		if err := c.marshal.Unmarshal([]byte(result), value); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *MultiLevelClient) GetString(ctx context.Context, key string) string {
+	key = c.GetKey(key)
+	if result, ok := c.local.Get(key); ok {
+		c.stats.localHits.Add(1)
+		return result
+	}
+	c.stats.localMisses.Add(1)
+	// 记录读取redis之前的失效版本，读取期间本地缓存被修改时不回填，避免旧值覆盖失效消息
+	generation := c.generation.Load()
+	var get *redis.StringCmd
+	var ttl *redis.DurationCmd
+	_, err := c.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		get = pipe.Get(ctx, key)
+		ttl = pipe.PTTL(ctx, key)
+		return nil
+	})
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			log.WithField("key", key).WithError(err).Warn("get remote cache failed")
+		}
+		c.stats.remoteMisses.Add(1)
+		return ""
+	}
+	c.stats.remoteHits.Add(1)
+	result := get.Val()
+	c.mu.Lock()
+	if c.generation.Load() == generation {
+		c.local.Set(key, result, c.localTTL(ttl.Val()))
+	}
+	c.mu.Unlock()
+	return result
+}
+
+func (c *MultiLevelClient) Delete(ctx context.Context, key string) bool {
+	key = c.GetKey(key)
+	c.deleteLocal(key)
+	result, err := c.rdb.Del(ctx, key).Result()
+	c.invalidate(ctx, key)
+	return err == nil && result > 0
+}
+
+func (c *MultiLevelClient) Exist(ctx context.Context, key string) bool {
+	key = c.GetKey(key)
+	if _, ok := c.local.Get(key); ok {
+		return true
+	}
+	result, err := c.rdb.Exists(ctx, key).Result()
+	return err == nil && result > 0
+}
+
+func (c *MultiLevelClient) Expire(ctx context.Context, key string, expiration time.Duration) error {
+	key = c.GetKey(key)
+	if err := c.rdb.Expire(ctx, key, expiration).Err(); err != nil {
+		return errorx.Wrap(err, "redis expire failed")
+	}
+	// 本地缓存的过期时间可能超过新的过期时间，直接删除
+	c.deleteLocal(key)
+	c.invalidate(ctx, key)
+	return nil
+}
+
+func (c *MultiLevelClient) Incr(ctx context.Context, key string) (int64, error) {
+	return c.IncrBy(ctx, key, 1)
+}
+
+func (c *MultiLevelClient) Decr(ctx context.Context, key string) (int64, error) {
+	return c.IncrBy(ctx, key, -1)
+}
+
+func (c *MultiLevelClient) DecrBy(ctx context.Context, key string, value int64) (int64, error) {
+	return c.IncrBy(ctx, key, -value)
+}
+
+// IncrBy 计数器可通过 GetString 读取，修改后需要删除本地缓存
+func (c *MultiLevelClient) IncrBy(ctx context.Context, key string, value int64) (int64, error) {
+	result, err := c.Structure.IncrBy(ctx, key, value)
+	if err == nil {
+		key = c.GetKey(key)
+		c.deleteLocal(key)
+		c.invalidate(ctx, key)
+	}
+	return result, err
+}
+
+// AcquireLock 锁由二级缓存实现，不经过本地缓存
+func (c *MultiLevelClient) AcquireLock(ctx context.Context, key, token string, ttl time.Duration, reentrant bool) (bool, error) {
+	return c.locker.AcquireLock(ctx, key, token, ttl, reentrant)
+}
+
+func (c *MultiLevelClient) ReleaseLock(ctx context.Context, key, token string) (bool, error) {
+	return c.locker.ReleaseLock(ctx, key, token)
+}
+
+func (c *MultiLevelClient) RenewLock(ctx context.Context, key, token string, ttl time.Duration) (bool, error) {
+	return c.locker.RenewLock(ctx, key, token, ttl)
+}
+
+// AllowTokenBucket 限流由二级缓存实现，多个实例共享限流状态
+func (c *MultiLevelClient) AllowTokenBucket(ctx context.Context, key string, rate float64, burst, n int64) (*RateResult, error) {
+	return c.limiter.AllowTokenBucket(ctx, key, rate, burst, n)
+}
+
+func (c *MultiLevelClient) AllowSlidingWindow(ctx context.Context, key string, limit int64, window time.Duration, n int64) (*RateResult, error) {
+	return c.limiter.AllowSlidingWindow(ctx, key, limit, window, n)
+}
+
+// 本地缓存过期时间，不超过redis中的剩余过期时间
+func (c *MultiLevelClient) localTTL(remote time.Duration) time.Duration {
+	if remote > 0 && remote < c.localExpire {
+		return remote
+	}
+	return c.localExpire
+}
+
+// 修改本地缓存并递增失效版本，修改之前开始读取redis的 GetString 不再回填本地缓存
+func (c *MultiLevelClient) updateLocal(fn func(local *LocalCache)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation.Add(1)
+	fn(c.local)
+}
+
+// 删除本地缓存
+func (c *MultiLevelClient) deleteLocal(keys ...string) {
+	c.updateLocal(func(local *LocalCache) {
+		for _, key := range keys {
+			local.Delete(key)
+		}
+	})
+}
+
+// 按照通配符删除本地缓存
+func (c *MultiLevelClient) deletePattern(pattern string) {
+	c.updateLocal(func(local *LocalCache) {
+		local.DeleteFunc(func(key string) bool { return matchPattern(pattern, key) })
+	})
+}
+
+// 广播失效消息，消息格式为"实例ID\nkey"
+func (c *MultiLevelClient) invalidate(ctx context.Context, key string) {
+	if err := c.rdb.Publish(ctx, InvalidateChannel, c.id+"\n"+key).Err(); err != nil {
+		log.WithField("key", key).WithError(err).Warn("publish cache invalidation failed")
+	}
+}
+
+// 订阅失效消息，删除其他实例修改的本地缓存
+func (c *MultiLevelClient) subscribe() error {
+	ctx := context.Background()
+	c.pubsub = c.rdb.Subscribe(ctx, InvalidateChannel, InvalidatePatternChannel)
+	for range 2 {
+		if _, err := c.pubsub.Receive(ctx); err != nil {
+			_ = c.pubsub.Close()
+			return err
+		}
+	}
+	go func() {
+		for msg := range c.pubsub.Channel() {
+			id, key, ok := strings.Cut(msg.Payload, "\n")
+			if !ok || id == c.id {
+				continue
+			}
+			if msg.Channel == InvalidatePatternChannel {
+				c.deletePattern(key)
+			} else {
+				c.deleteLocal(key)
+			}
+			c.stats.invalidations.Add(1)
+		}
+	}()
+	return nil
+}
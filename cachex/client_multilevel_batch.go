@@ -0,0 +1,100 @@
+package cachex
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	log "github.com/sirupsen/logrus"
+)
+
+func (c *MultiLevelClient) MSet(ctx context.Context, values map[string]any, expiration time.Duration) error {
+	return pipelineMSet(ctx, c, values, expiration)
+}
+
+// MGetString 直接读取redis，不读取也不写入本地缓存
+func (c *MultiLevelClient) MGetString(ctx context.Context, keys ...string) (map[string]string, error) {
+	return c.batch.MGetString(ctx, keys...)
+}
+
+func (c *MultiLevelClient) DeleteMany(ctx context.Context, keys ...string) (int64, error) {
+	deleted, err := c.batch.DeleteMany(ctx, keys...)
+	c.invalidateMany(ctx, c.GetConfig().GetKeys(keys))
+	return deleted, err
+}
+
+func (c *MultiLevelClient) DeleteByPattern(ctx context.Context, pattern string) (int64, error) {
+	deleted, err := c.batch.DeleteByPattern(ctx, pattern)
+	pattern = c.GetKey(pattern)
+	c.deletePattern(pattern)
+	if err := c.rdb.Publish(ctx, InvalidatePatternChannel, c.id+"\n"+pattern).Err(); err != nil {
+		log.WithField("pattern", pattern).WithError(err).Warn("publish cache invalidation failed")
+	}
+	return deleted, err
+}
+
+func (c *MultiLevelClient) Pipelined(ctx context.Context, fn func(pipe Pipeline) error) error {
+	return c.pipelined(ctx, fn, c.batch.Pipelined)
+}
+
+func (c *MultiLevelClient) TxPipelined(ctx context.Context, fn func(pipe Pipeline) error) error {
+	return c.pipelined(ctx, fn, c.batch.TxPipelined)
+}
+
+// 在二级缓存中执行管道，执行后删除修改过的本地缓存并广播失效消息
+func (c *MultiLevelClient) pipelined(ctx context.Context, fn func(pipe Pipeline) error,
+	exec func(ctx context.Context, fn func(pipe Pipeline) error) error) error {
+	var keys []string
+	err := exec(ctx, func(pipe Pipeline) error {
+		return fn(&invalidatingPipeline{Pipeline: pipe, config: c.GetConfig(), keys: &keys})
+	})
+	c.invalidateMany(ctx, keys)
+	return err
+}
+
+// 批量删除本地缓存并通过管道广播失效消息
+func (c *MultiLevelClient) invalidateMany(ctx context.Context, keys []string) {
+	if len(keys) == 0 {
+		return
+	}
+	c.deleteLocal(keys...)
+	err := inBatches(keys, func(keys []string) error {
+		_, err := c.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			for _, key := range keys {
+				pipe.Publish(ctx, InvalidateChannel, c.id+"\n"+key)
+			}
+			return nil
+		})
+		return err
+	})
+	if err != nil {
+		log.WithField("keys", len(keys)).WithError(err).Warn("publish cache invalidation failed")
+	}
+}
+
+// 记录修改过的key的管道
+type invalidatingPipeline struct {
+	Pipeline
+	config *Config
+	keys   *[]string // 添加前缀后的key
+}
+
+func (p *invalidatingPipeline) Set(key string, value any, expiration time.Duration) *Cmd {
+	*p.keys = append(*p.keys, p.config.GetKey(key))
+	return p.Pipeline.Set(key, value, expiration)
+}
+
+func (p *invalidatingPipeline) Delete(key string) *Cmd {
+	*p.keys = append(*p.keys, p.config.GetKey(key))
+	return p.Pipeline.Delete(key)
+}
+
+func (p *invalidatingPipeline) Expire(key string, expiration time.Duration) *Cmd {
+	*p.keys = append(*p.keys, p.config.GetKey(key))
+	return p.Pipeline.Expire(key, expiration)
+}
+
+func (p *invalidatingPipeline) IncrBy(key string, value int64) *Cmd {
+	*p.keys = append(*p.keys, p.config.GetKey(key))
+	return p.Pipeline.IncrBy(key, value)
+}
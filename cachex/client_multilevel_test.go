@@ -0,0 +1,147 @@
+package cachex
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestMultiLevelClient(t *testing.T) {
+	c1, err := NewMultiLevelClient(&Config{Source: "c1", Remote: "memredis", Marshal: "json", MaxEntries: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c1.Close()
+	address := c1.GetRemote().GetConfig().Address
+	c2, err := NewMultiLevelClient(&Config{Source: "c2", Remote: "redis", Address: address, Marshal: "json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c2.Close()
+
+	ctx := context.Background()
+	if err = c1.Set(ctx, "key", "v1", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	waitInvalidations(c2, 1)
+	// c2 未命中本地缓存，读取redis后写入本地缓存
+	if value := c2.GetString(ctx, "key"); value != `"v1"` {
+		t.Fatalf("unexpected value: %s", value)
+	}
+	if value := c2.GetString(ctx, "key"); value != `"v1"` {
+		t.Fatalf("unexpected value: %s", value)
+	}
+	if stats := c2.Stats(); stats.LocalHits != 1 || stats.RemoteHits != 1 || stats.LocalHitRatio() != 0.5 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+
+	// c1 更新后广播失效消息，c2 删除本地缓存
+	if err = c1.Set(ctx, "key", "v2", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	waitInvalidations(c2, 2)
+	var value string
+	if !c2.Get(ctx, "key", &value) || value != "v2" {
+		t.Fatalf("expected invalidated value v2, got %s", value)
+	}
+
+	// 本地缓存过期时间不超过redis剩余过期时间
+	if err = c1.Set(ctx, "short", "v", 50*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if c1.Exist(ctx, "short") {
+		t.Fatal("local cache should expire with remote ttl")
+	}
+
+	// 本地缓存容量有限
+	for _, key := range []string{"a", "b", "c"} {
+		_ = c1.Set(ctx, key, key, time.Minute)
+	}
+	if size := c1.Stats().LocalSize; size != 2 {
+		t.Fatalf("local cache should be bounded, got %d", size)
+	}
+	waitInvalidations(c2, 6)
+
+	// 批量操作同样广播失效消息
+	_ = c2.GetString(ctx, "a")
+	if err = c1.MSet(ctx, map[string]any{"a": "x"}, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	waitInvalidations(c2, 7)
+	if value = c2.GetString(ctx, "a"); value != `"x"` {
+		t.Fatalf("expected invalidated value x, got %s", value)
+	}
+	if _, err = c1.DeleteByPattern(ctx, "*"); err != nil {
+		t.Fatal(err)
+	}
+	waitInvalidations(c2, 8)
+	if c2.Exist(ctx, "a") {
+		t.Fatal("local cache should be invalidated by pattern")
+	}
+}
+
+// 等待收到指定数量的失效消息
+func waitInvalidations(c *MultiLevelClient, n uint64) {
+	deadline := time.Now().Add(time.Second)
+	for c.Stats().Invalidations < n && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// 在管道执行完成后、回填本地缓存之前执行指定函数，用于模拟读取期间收到失效消息
+type interleaveHook struct {
+	fn func()
+}
+
+func (h *interleaveHook) DialHook(next redis.DialHook) redis.DialHook { return next }
+
+func (h *interleaveHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook { return next }
+
+func (h *interleaveHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		err := next(ctx, cmds)
+		if fn := h.fn; fn != nil {
+			h.fn = nil
+			fn()
+		}
+		return err
+	}
+}
+
+func TestMultiLevelClientInterleave(t *testing.T) {
+	c1, err := NewMultiLevelClient(&Config{Source: "c1", Remote: "memredis", Marshal: "json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c1.Close()
+	address := c1.GetRemote().GetConfig().Address
+	c2, err := NewMultiLevelClient(&Config{Source: "c2", Remote: "redis", Address: address, Marshal: "json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c2.Close()
+
+	ctx := context.Background()
+	if err = c1.Set(ctx, "key", "v1", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	waitInvalidations(c2, 1)
+
+	// c2 读取到旧值之后，c1 写入新值并且 c2 已处理失效消息，c2 不应将旧值回填至本地缓存
+	hook := &interleaveHook{fn: func() {
+		if err := c1.Set(ctx, "key", "v2", time.Minute); err != nil {
+			t.Error(err)
+		}
+		waitInvalidations(c2, 2)
+	}}
+	c2.rdb.AddHook(hook)
+	if value := c2.GetString(ctx, "key"); value != `"v1"` {
+		t.Fatalf("unexpected value: %s", value)
+	}
+	if value := c2.GetString(ctx, "key"); value != `"v2"` {
+		t.Fatalf("stale value should not be cached locally, got %s", value)
+	}
+}
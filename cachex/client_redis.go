@@ -96,6 +96,13 @@ func (c *RedisClient) Close() error {
 	return nil
 }
 
+func (c *RedisClient) HealthCheck(ctx context.Context) error {
+	if err := c.client.Ping(ctx).Err(); err != nil {
+		return errorx.Wrap(err, "ping redis failed")
+	}
+	return nil
+}
+
 func (c *RedisClient) GetKey(key string) string {
 	return c.GetConfig().GetKey(key)
 }
@@ -0,0 +1,139 @@
+package cachex
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-xuan/utilx/errorx"
+	"github.com/redis/go-redis/v9"
+)
+
+// SCAN每次迭代返回的参考数量
+const scanCount = 1000
+
+func (c *RedisClient) MSet(ctx context.Context, values map[string]any, expiration time.Duration) error {
+	return pipelineMSet(ctx, c, values, expiration)
+}
+
+func (c *RedisClient) MGetString(ctx context.Context, keys ...string) (map[string]string, error) {
+	return pipelineMGet(ctx, c, keys)
+}
+
+// DeleteMany 通过管道逐个删除，集群模式下key无需位于同一slot
+func (c *RedisClient) DeleteMany(ctx context.Context, keys ...string) (int64, error) {
+	var deleted int64
+	err := inBatches(c.GetConfig().GetKeys(keys), func(keys []string) error {
+		n, err := unlinkKeys(ctx, c.GetClient(), keys)
+		deleted += n
+		return err
+	})
+	if err != nil {
+		return deleted, errorx.Wrap(err, "redis delete many failed")
+	}
+	return deleted, nil
+}
+
+// DeleteByPattern 通过SCAN遍历匹配的key并删除，集群模式下遍历全部主节点，不会像KEYS一样阻塞redis
+func (c *RedisClient) DeleteByPattern(ctx context.Context, pattern string) (int64, error) {
+	pattern = c.GetKey(pattern)
+	var deleted atomic.Int64
+	var err error
+	if cluster, ok := c.GetClient().(*redis.ClusterClient); ok {
+		err = cluster.ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+			return scanDelete(ctx, node, pattern, &deleted)
+		})
+	} else {
+		err = scanDelete(ctx, c.GetClient(), pattern, &deleted)
+	}
+	if err != nil {
+		return deleted.Load(), errorx.Wrap(err, "redis delete by pattern failed")
+	}
+	return deleted.Load(), nil
+}
+
+func (c *RedisClient) Pipelined(ctx context.Context, fn func(pipe Pipeline) error) error {
+	return runPipeline(c.GetConfig(), fn, func(cmds []*pipelineCmd) {
+		execRedisPipeline(ctx, c.GetClient().Pipeline(), cmds)
+	})
+}
+
+// TxPipelined 使用MULTI/EXEC执行，集群模式下全部key需要位于同一slot（例如使用{hashtag}）
+func (c *RedisClient) TxPipelined(ctx context.Context, fn func(pipe Pipeline) error) error {
+	return runPipeline(c.GetConfig(), fn, func(cmds []*pipelineCmd) {
+		execRedisPipeline(ctx, c.GetClient().TxPipeline(), cmds)
+	})
+}
+
+// 执行管道命令，错误记录在各命令中
+func execRedisPipeline(ctx context.Context, pipe redis.Pipeliner, cmds []*pipelineCmd) {
+	results := make([]redis.Cmder, len(cmds))
+	for i, cmd := range cmds {
+		switch cmd.op {
+		case opSet:
+			results[i] = pipe.Set(ctx, cmd.key, cmd.value, cmd.expiration)
+		case opGet:
+			results[i] = pipe.Get(ctx, cmd.key)
+		case opDelete:
+			results[i] = pipe.Del(ctx, cmd.key)
+		case opExpire:
+			results[i] = pipe.Expire(ctx, cmd.key, cmd.expiration)
+		case opIncrBy:
+			results[i] = pipe.IncrBy(ctx, cmd.key, cmd.increment)
+		}
+	}
+	_, _ = pipe.Exec(ctx)
+	for i, cmd := range cmds {
+		err := redisError(results[i].Err(), "redis pipeline failed")
+		switch result := results[i].(type) {
+		case *redis.StatusCmd:
+			cmd.set(result.Val(), err)
+		case *redis.StringCmd:
+			cmd.set(result.Val(), err)
+		case *redis.IntCmd:
+			cmd.set(strconv.FormatInt(result.Val(), 10), err)
+		case *redis.BoolCmd:
+			cmd.set(boolString(result.Val()), err)
+		}
+	}
+}
+
+// 遍历单个节点中匹配的key并删除
+func scanDelete(ctx context.Context, client redis.Cmdable, pattern string, deleted *atomic.Int64) error {
+	var cursor uint64
+	for {
+		keys, next, err := client.Scan(ctx, cursor, pattern, scanCount).Result()
+		if err != nil {
+			return err
+		}
+		n, err := unlinkKeys(ctx, client, keys)
+		deleted.Add(n)
+		if err != nil {
+			return err
+		}
+		if cursor = next; cursor == 0 {
+			return nil
+		}
+	}
+}
+
+// 通过管道逐个异步删除key，返回删除数量
+func unlinkKeys(ctx context.Context, client redis.Cmdable, keys []string) (int64, error) {
+	if len(keys) == 0 {
+		return 0, nil
+	}
+	cmds, err := client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, key := range keys {
+			pipe.Unlink(ctx, key)
+		}
+		return nil
+	})
+	var deleted int64
+	for _, cmd := range cmds {
+		if result, ok := cmd.(*redis.IntCmd); ok {
+			deleted += result.Val()
+		}
+	}
+	return deleted, err
+}
@@ -0,0 +1,62 @@
+package cachex
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// 加锁：锁不存在时创建，可重入时相同token的重入次数加1，成功返回1
+var acquireLockScript = redis.NewScript(`
+if redis.call('exists', KEYS[1]) == 0 then
+	redis.call('hset', KEYS[1], ARGV[1], 1)
+	redis.call('pexpire', KEYS[1], ARGV[2])
+	return 1
+end
+if ARGV[3] == '1' and redis.call('hexists', KEYS[1], ARGV[1]) == 1 then
+	redis.call('hincrby', KEYS[1], ARGV[1], 1)
+	redis.call('pexpire', KEYS[1], ARGV[2])
+	return 1
+end
+return 0
+`)
+
+// 释放锁：校验token后重入次数减1，减为0时删除，未持有锁返回0
+var releaseLockScript = redis.NewScript(`
+if redis.call('hexists', KEYS[1], ARGV[1]) == 0 then
+	return 0
+end
+if redis.call('hincrby', KEYS[1], ARGV[1], -1) <= 0 then
+	redis.call('del', KEYS[1])
+end
+return 1
+`)
+
+// 续期：校验token后更新过期时间，未持有锁返回0
+var renewLockScript = redis.NewScript(`
+if redis.call('hexists', KEYS[1], ARGV[1]) == 0 then
+	return 0
+end
+redis.call('pexpire', KEYS[1], ARGV[2])
+return 1
+`)
+
+func (c *RedisClient) AcquireLock(ctx context.Context, key, token string, ttl time.Duration, reentrant bool) (bool, error) {
+	reentrantArg := "0"
+	if reentrant {
+		reentrantArg = "1"
+	}
+	result, err := acquireLockScript.Run(ctx, c.GetClient(), []string{c.GetKey(key)}, token, ttl.Milliseconds(), reentrantArg).Int()
+	return result == 1, redisError(err, "redis acquire lock failed")
+}
+
+func (c *RedisClient) ReleaseLock(ctx context.Context, key, token string) (bool, error) {
+	result, err := releaseLockScript.Run(ctx, c.GetClient(), []string{c.GetKey(key)}, token).Int()
+	return result == 1, redisError(err, "redis release lock failed")
+}
+
+func (c *RedisClient) RenewLock(ctx context.Context, key, token string, ttl time.Duration) (bool, error) {
+	result, err := renewLockScript.Run(ctx, c.GetClient(), []string{c.GetKey(key)}, token, ttl.Milliseconds()).Int()
+	return result == 1, redisError(err, "redis renew lock failed")
+}
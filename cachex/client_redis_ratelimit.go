@@ -0,0 +1,96 @@
+package cachex
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// 令牌桶：按照经过的时间补充令牌后扣减，返回{是否允许, 剩余令牌, 重试等待毫秒数}
+// 使用redis服务端时间，避免多个实例的时钟偏差影响令牌补充（脚本中写入前调用TIME需要redis 5.0及以上版本）
+var tokenBucketScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local n = tonumber(ARGV[3])
+local time = redis.call('time')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+local state = redis.call('hmget', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1]) or burst
+local ts = tonumber(state[2]) or now
+if now > ts then
+	tokens = math.min(burst, tokens + (now - ts) * rate / 1000)
+	ts = now
+end
+local allowed, retry = 0, 0
+if tokens >= n then
+	tokens = tokens - n
+	allowed = 1
+else
+	retry = math.ceil((n - tokens) * 1000 / rate)
+end
+redis.call('hset', KEYS[1], 'tokens', tostring(tokens), 'ts', tostring(ts))
+redis.call('pexpire', KEYS[1], ARGV[4])
+return {allowed, math.floor(tokens), retry}
+`)
+
+// 滑动窗口：删除窗口外的请求后计数，返回{是否允许, 剩余数量, 重试等待毫秒数}
+// 使用redis服务端时间，避免多个实例的时钟偏差影响窗口计算
+var slidingWindowScript = redis.NewScript(`
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local n = tonumber(ARGV[3])
+local time = redis.call('time')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+redis.call('zremrangebyscore', KEYS[1], '-inf', now - window)
+local count = redis.call('zcard', KEYS[1])
+if count + n <= limit then
+	for i = 1, n do
+		redis.call('zadd', KEYS[1], now, ARGV[4] .. i)
+	end
+	redis.call('pexpire', KEYS[1], window)
+	return {1, limit - count - n, 0}
+end
+local retry = window
+local index = count + n - limit - 1
+if index < count then
+	local oldest = redis.call('zrange', KEYS[1], index, index, 'WITHSCORES')
+	retry = tonumber(oldest[2]) + window - now
+end
+return {0, math.max(limit - count, 0), retry}
+`)
+
+func (c *RedisClient) AllowTokenBucket(ctx context.Context, key string, rate float64, burst, n int64) (*RateResult, error) {
+	values, err := tokenBucketScript.Run(ctx, c.GetClient(), []string{c.GetKey(key)},
+		strconv.FormatFloat(rate, 'f', -1, 64), burst, n, tokenBucketTTL(rate, burst)).Int64Slice()
+	if err != nil {
+		return nil, redisError(err, "redis token bucket failed")
+	}
+	return rateResult(values, burst), nil
+}
+
+func (c *RedisClient) AllowSlidingWindow(ctx context.Context, key string, limit int64, window time.Duration, n int64) (*RateResult, error) {
+	// 成员前缀保证同一毫秒内的请求不会重复
+	member := uuid.NewString() + ":"
+	values, err := slidingWindowScript.Run(ctx, c.GetClient(), []string{c.GetKey(key)},
+		limit, window.Milliseconds(), n, member).Int64Slice()
+	if err != nil {
+		return nil, redisError(err, "redis sliding window failed")
+	}
+	return rateResult(values, limit), nil
+}
+
+// 转换lua脚本返回的{是否允许, 剩余数量, 重试等待毫秒数}
+func rateResult(values []int64, limit int64) *RateResult {
+	if len(values) != 3 {
+		return &RateResult{Limit: limit}
+	}
+	return &RateResult{
+		Allowed:    values[0] == 1,
+		Limit:      limit,
+		Remaining:  values[1],
+		RetryAfter: time.Duration(values[2]) * time.Millisecond,
+	}
+}
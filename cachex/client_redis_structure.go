@@ -0,0 +1,225 @@
+package cachex
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/go-xuan/utilx/errorx"
+	"github.com/redis/go-redis/v9"
+)
+
+// 转换redis错误，key或者成员不存在时返回 ErrNil，类型不匹配时返回 ErrWrongType
+func redisError(err error, message string) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, redis.Nil):
+		return ErrNil
+	case strings.HasPrefix(err.Error(), "WRONGTYPE"):
+		return ErrWrongType
+	case strings.Contains(err.Error(), "not an integer"):
+		return ErrNotInteger
+	}
+	return errorx.Wrap(err, message)
+}
+
+// redis分数范围参数
+func redisScore(score float64) string {
+	if math.IsInf(score, 1) {
+		return "+inf"
+	} else if math.IsInf(score, -1) {
+		return "-inf"
+	}
+	return strconv.FormatFloat(score, 'f', -1, 64)
+}
+
+func toZ(members []redis.Z) []Z {
+	result := make([]Z, 0, len(members))
+	for _, member := range members {
+		result = append(result, Z{Member: member.Member.(string), Score: member.Score})
+	}
+	return result
+}
+
+func (c *RedisClient) Incr(ctx context.Context, key string) (int64, error) {
+	result, err := c.GetClient().Incr(ctx, c.GetKey(key)).Result()
+	return result, redisError(err, "redis incr failed")
+}
+
+func (c *RedisClient) IncrBy(ctx context.Context, key string, value int64) (int64, error) {
+	result, err := c.GetClient().IncrBy(ctx, c.GetKey(key), value).Result()
+	return result, redisError(err, "redis incrby failed")
+}
+
+func (c *RedisClient) Decr(ctx context.Context, key string) (int64, error) {
+	result, err := c.GetClient().Decr(ctx, c.GetKey(key)).Result()
+	return result, redisError(err, "redis decr failed")
+}
+
+func (c *RedisClient) DecrBy(ctx context.Context, key string, value int64) (int64, error) {
+	result, err := c.GetClient().DecrBy(ctx, c.GetKey(key), value).Result()
+	return result, redisError(err, "redis decrby failed")
+}
+
+func (c *RedisClient) HSet(ctx context.Context, key string, values map[string]string) (int64, error) {
+	if len(values) == 0 {
+		return 0, nil
+	}
+	result, err := c.GetClient().HSet(ctx, c.GetKey(key), values).Result()
+	return result, redisError(err, "redis hset failed")
+}
+
+func (c *RedisClient) HGet(ctx context.Context, key, field string) (string, error) {
+	result, err := c.GetClient().HGet(ctx, c.GetKey(key), field).Result()
+	return result, redisError(err, "redis hget failed")
+}
+
+func (c *RedisClient) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	result, err := c.GetClient().HGetAll(ctx, c.GetKey(key)).Result()
+	return result, redisError(err, "redis hgetall failed")
+}
+
+func (c *RedisClient) HDel(ctx context.Context, key string, fields ...string) (int64, error) {
+	if len(fields) == 0 {
+		return 0, nil
+	}
+	result, err := c.GetClient().HDel(ctx, c.GetKey(key), fields...).Result()
+	return result, redisError(err, "redis hdel failed")
+}
+
+func (c *RedisClient) HLen(ctx context.Context, key string) (int64, error) {
+	result, err := c.GetClient().HLen(ctx, c.GetKey(key)).Result()
+	return result, redisError(err, "redis hlen failed")
+}
+
+func (c *RedisClient) LPush(ctx context.Context, key string, values ...string) (int64, error) {
+	if len(values) == 0 {
+		return c.LLen(ctx, key)
+	}
+	result, err := c.GetClient().LPush(ctx, c.GetKey(key), toAny(values)...).Result()
+	return result, redisError(err, "redis lpush failed")
+}
+
+func (c *RedisClient) RPush(ctx context.Context, key string, values ...string) (int64, error) {
+	if len(values) == 0 {
+		return c.LLen(ctx, key)
+	}
+	result, err := c.GetClient().RPush(ctx, c.GetKey(key), toAny(values)...).Result()
+	return result, redisError(err, "redis rpush failed")
+}
+
+func (c *RedisClient) LPop(ctx context.Context, key string) (string, error) {
+	result, err := c.GetClient().LPop(ctx, c.GetKey(key)).Result()
+	return result, redisError(err, "redis lpop failed")
+}
+
+func (c *RedisClient) RPop(ctx context.Context, key string) (string, error) {
+	result, err := c.GetClient().RPop(ctx, c.GetKey(key)).Result()
+	return result, redisError(err, "redis rpop failed")
+}
+
+func (c *RedisClient) LRange(ctx context.Context, key string, start, stop int64) ([]string, error) {
+	result, err := c.GetClient().LRange(ctx, c.GetKey(key), start, stop).Result()
+	return result, redisError(err, "redis lrange failed")
+}
+
+func (c *RedisClient) LLen(ctx context.Context, key string) (int64, error) {
+	result, err := c.GetClient().LLen(ctx, c.GetKey(key)).Result()
+	return result, redisError(err, "redis llen failed")
+}
+
+func (c *RedisClient) SAdd(ctx context.Context, key string, members ...string) (int64, error) {
+	if len(members) == 0 {
+		return 0, nil
+	}
+	result, err := c.GetClient().SAdd(ctx, c.GetKey(key), toAny(members)...).Result()
+	return result, redisError(err, "redis sadd failed")
+}
+
+func (c *RedisClient) SRem(ctx context.Context, key string, members ...string) (int64, error) {
+	if len(members) == 0 {
+		return 0, nil
+	}
+	result, err := c.GetClient().SRem(ctx, c.GetKey(key), toAny(members)...).Result()
+	return result, redisError(err, "redis srem failed")
+}
+
+func (c *RedisClient) SMembers(ctx context.Context, key string) ([]string, error) {
+	result, err := c.GetClient().SMembers(ctx, c.GetKey(key)).Result()
+	return result, redisError(err, "redis smembers failed")
+}
+
+func (c *RedisClient) SIsMember(ctx context.Context, key, member string) (bool, error) {
+	result, err := c.GetClient().SIsMember(ctx, c.GetKey(key), member).Result()
+	return result, redisError(err, "redis sismember failed")
+}
+
+func (c *RedisClient) SCard(ctx context.Context, key string) (int64, error) {
+	result, err := c.GetClient().SCard(ctx, c.GetKey(key)).Result()
+	return result, redisError(err, "redis scard failed")
+}
+
+func (c *RedisClient) ZAdd(ctx context.Context, key string, members ...Z) (int64, error) {
+	if len(members) == 0 {
+		return 0, nil
+	}
+	values := make([]redis.Z, 0, len(members))
+	for _, member := range members {
+		values = append(values, redis.Z{Score: member.Score, Member: member.Member})
+	}
+	result, err := c.GetClient().ZAdd(ctx, c.GetKey(key), values...).Result()
+	return result, redisError(err, "redis zadd failed")
+}
+
+func (c *RedisClient) ZIncrBy(ctx context.Context, key string, increment float64, member string) (float64, error) {
+	result, err := c.GetClient().ZIncrBy(ctx, c.GetKey(key), increment, member).Result()
+	return result, redisError(err, "redis zincrby failed")
+}
+
+func (c *RedisClient) ZRem(ctx context.Context, key string, members ...string) (int64, error) {
+	if len(members) == 0 {
+		return 0, nil
+	}
+	result, err := c.GetClient().ZRem(ctx, c.GetKey(key), toAny(members)...).Result()
+	return result, redisError(err, "redis zrem failed")
+}
+
+func (c *RedisClient) ZScore(ctx context.Context, key, member string) (float64, error) {
+	result, err := c.GetClient().ZScore(ctx, c.GetKey(key), member).Result()
+	return result, redisError(err, "redis zscore failed")
+}
+
+func (c *RedisClient) ZRangeByScore(ctx context.Context, key string, min, max float64) ([]Z, error) {
+	result, err := c.GetClient().ZRangeByScoreWithScores(ctx, c.GetKey(key), &redis.ZRangeBy{
+		Min: redisScore(min),
+		Max: redisScore(max),
+	}).Result()
+	if err != nil {
+		return nil, redisError(err, "redis zrangebyscore failed")
+	}
+	return toZ(result), nil
+}
+
+func (c *RedisClient) ZRevRange(ctx context.Context, key string, start, stop int64) ([]Z, error) {
+	result, err := c.GetClient().ZRevRangeWithScores(ctx, c.GetKey(key), start, stop).Result()
+	if err != nil {
+		return nil, redisError(err, "redis zrevrange failed")
+	}
+	return toZ(result), nil
+}
+
+func (c *RedisClient) ZCard(ctx context.Context, key string) (int64, error) {
+	result, err := c.GetClient().ZCard(ctx, c.GetKey(key)).Result()
+	return result, redisError(err, "redis zcard failed")
+}
+
+func toAny(values []string) []any {
+	result := make([]any, len(values))
+	for i, value := range values {
+		result[i] = value
+	}
+	return result
+}
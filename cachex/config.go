@@ -2,7 +2,6 @@ package cachex
 
 import (
 	"github.com/go-xuan/utilx/errorx"
-	"github.com/go-xuan/utilx/stringx"
 	log "github.com/sirupsen/logrus"
 
 	"github.com/go-xuan/quanx/configx"
@@ -11,34 +10,49 @@ import (
 
 // Config 缓存配置
 type Config struct {
-	Source   string `json:"source" yaml:"source" default:"default"`     // 缓存源名称
-	Driver   string `json:"driver" yaml:"driver" default:"local"`       // 客户端驱动（redis/local）
-	Enable   bool   `json:"enable" yaml:"enable"`                       // 数据源启用
-	Address  string `json:"address" yaml:"address" default:"localhost"` // 主机
-	Username string `json:"username" yaml:"username"`                   // 用户名
-	Password string `json:"password" yaml:"password"`                   // 密码
-	Database int    `json:"database" yaml:"database"`                   // 数据库，默认0
-	Prefix   string `json:"prefix" yaml:"prefix"`                       // 缓存key前缀
-	Mode     int    `json:"mode" yaml:"mode"`                           // redis模式（0-单机/1-集群/3-哨兵，默认单机模式）
-	Master   string `json:"master" yaml:"master"`                       // redis哨兵模式主服务器名称
-	PoolSize int    `json:"poolSize" yaml:"poolSize"`                   // redis连接池大小
-	Marshal  string `json:"marshal" yaml:"marshal" default:"json"`      // 序列化方式（json/yaml）
+	Source          string    `json:"source" yaml:"source" default:"default" comment:"缓存源名称"`                                                      // 缓存源名称
+	Driver          string    `json:"driver" yaml:"driver" default:"local" comment:"客户端驱动（redis/local/memredis/multilevel）"`                       // 客户端驱动（redis/local/memredis/multilevel）
+	Enable          bool      `json:"enable" yaml:"enable" comment:"数据源启用"`                                                                        // 数据源启用
+	Address         string    `json:"address" yaml:"address" default:"localhost" comment:"主机"`                                                     // 主机
+	Username        string    `json:"username" yaml:"username" comment:"用户名"`                                                                      // 用户名
+	Password        string    `json:"password" yaml:"password" secret:"true" comment:"密码"`                                                         // 密码
+	Database        int       `json:"database" yaml:"database" comment:"数据库，默认0"`                                                                  // 数据库，默认0
+	Prefix          string    `json:"prefix" yaml:"prefix" comment:"缓存key前缀"`                                                                      // 缓存key前缀
+	Mode            int       `json:"mode" yaml:"mode" validate:"oneof=0 1 2" comment:"redis模式（0-单机/1-集群/2-哨兵，默认单机模式）"`                            // redis模式（0-单机/1-集群/2-哨兵，默认单机模式）
+	Master          string    `json:"master" yaml:"master" comment:"redis哨兵模式主服务器名称"`                                                              // redis哨兵模式主服务器名称
+	PoolSize        int       `json:"poolSize" yaml:"poolSize" validate:"min=0" comment:"redis连接池大小"`                                              // redis连接池大小
+	Marshal         string    `json:"marshal" yaml:"marshal" default:"json" comment:"序列化方式（json/yaml）"`                                            // 序列化方式（json/yaml）
+	Remote          string    `json:"remote" yaml:"remote" default:"redis" comment:"多级缓存的远程缓存驱动（redis/memredis）"`                                  // 多级缓存的远程缓存驱动（redis/memredis）
+	LocalExpire     int       `json:"localExpire" yaml:"localExpire" default:"60" validate:"min=0" comment:"多级缓存的本地缓存最长过期时间（秒）"`                   // 多级缓存的本地缓存最长过期时间（秒）
+	MaxEntries      int       `json:"maxEntries" yaml:"maxEntries" validate:"min=0" comment:"本地缓存最大数量，0表示不限制"`                                     // 本地缓存最大数量，0表示不限制
+	MaxBytes        int64     `json:"maxBytes" yaml:"maxBytes" validate:"min=0" comment:"本地缓存最大内存（字节），0表示不限制"`                                     // 本地缓存最大内存（字节），0表示不限制
+	Eviction        string    `json:"eviction" yaml:"eviction" default:"lru" validate:"oneof=lru lfu tinylfu" comment:"本地缓存淘汰策略（lru/lfu/tinylfu）"` // 本地缓存淘汰策略（lru/lfu/tinylfu）
+	JanitorInterval int       `json:"janitorInterval" yaml:"janitorInterval" default:"60" comment:"本地缓存过期清理间隔（秒），默认60，小于0时不清理"`                    // 本地缓存过期清理间隔（秒），默认60，小于0时不清理
+	OnEvict         EvictFunc `json:"-" yaml:"-"`                                                                                                  // 本地缓存淘汰回调
 }
 
 // Copy 复制配置
 func (c *Config) Copy() *Config {
 	return &Config{
-		Source:   c.Source,
-		Driver:   c.Driver,
-		Enable:   c.Enable,
-		Address:  c.Address,
-		Username: c.Username,
-		Password: c.Password,
-		Database: c.Database,
-		Mode:     c.Mode,
-		Prefix:   c.Prefix,
-		Marshal:  c.Marshal,
-		Master:   c.Master,
+		Source:          c.Source,
+		Driver:          c.Driver,
+		Enable:          c.Enable,
+		Address:         c.Address,
+		Username:        c.Username,
+		Password:        c.Password,
+		Database:        c.Database,
+		Mode:            c.Mode,
+		Prefix:          c.Prefix,
+		Marshal:         c.Marshal,
+		Master:          c.Master,
+		PoolSize:        c.PoolSize,
+		Remote:          c.Remote,
+		LocalExpire:     c.LocalExpire,
+		MaxEntries:      c.MaxEntries,
+		MaxBytes:        c.MaxBytes,
+		Eviction:        c.Eviction,
+		JanitorInterval: c.JanitorInterval,
+		OnEvict:         c.OnEvict,
 	}
 }
 
@@ -67,10 +81,16 @@ func (c *Config) Readers() []configx.Reader {
 	return []configx.Reader{
 		nacosx.NewReader("cache.yaml"),
 		configx.NewFileReader("cache.yaml"),
+		configx.NewEnvReader(configx.EnvPrefix + "_CACHE"),
 	}
 }
 
 func (c *Config) Execute() error {
+	return c.ExecuteWith(registry.Pool())
+}
+
+// ExecuteWith 创建客户端并添加到指定的客户端池
+func (c *Config) ExecuteWith(p *configx.Pool[Client]) error {
 	if c.Enable {
 		logger := log.WithFields(c.LogFields())
 		client, err := NewClient(c)
@@ -78,7 +98,7 @@ func (c *Config) Execute() error {
 			logger.WithError(err).Error("create cache client failed")
 			return errorx.Wrap(err, "create cache client failed")
 		}
-		AddClient(c.Source, client)
+		p.Add(c.Source, client)
 		logger.Info("init cache success")
 	}
 	return nil
@@ -92,13 +112,12 @@ func (c *Config) GetKey(key string) string {
 	return key
 }
 
-// GetKeys 获取缓存keys
+// GetKeys 获取缓存keys，前缀规则与 GetKey 一致
 func (c *Config) GetKeys(keys []string) []string {
 	if len(keys) > 0 && c.Prefix != "" {
-		var newKeys []string
-		prefix := stringx.AddSuffix(c.Prefix, ":")
+		newKeys := make([]string, 0, len(keys))
 		for _, key := range keys {
-			newKeys = append(newKeys, prefix+key)
+			newKeys = append(newKeys, c.GetKey(key))
 		}
 		return newKeys
 	}
@@ -115,16 +134,22 @@ func (s Configs) Readers() []configx.Reader {
 	return []configx.Reader{
 		nacosx.NewReader("cache.yaml"),
 		configx.NewFileReader("cache.yaml"),
+		configx.NewEnvReader(configx.EnvPrefix + "_CACHE"),
 	}
 }
 
 func (s Configs) Execute() error {
+	return s.ExecuteWith(registry.Pool())
+}
+
+// ExecuteWith 创建客户端并添加到指定的客户端池
+func (s Configs) ExecuteWith(p *configx.Pool[Client]) error {
 	for _, config := range s {
-		if err := config.Execute(); err != nil {
+		if err := config.ExecuteWith(p); err != nil {
 			return errorx.Wrap(err, "cache config execute failed")
 		}
 	}
-	if !Initialized() {
+	if !p.Initialized() {
 		err := errorx.New("no enabled cache source")
 		log.WithField("error", err.Error()).Warn("init cache failed")
 		return err
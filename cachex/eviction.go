@@ -0,0 +1,270 @@
+package cachex
+
+import (
+	"container/heap"
+	"container/list"
+	"hash/maphash"
+)
+
+// 未限制最大数量时tinylfu频率估计使用的容量
+const defaultSketchSize = 10000
+
+// 淘汰策略，方法均在 LocalCache 的锁内调用
+type evictionPolicy interface {
+	record(key string)        // 记录访问，包括未命中的访问
+	add(entry *cacheEntry)    // 添加缓存
+	access(entry *cacheEntry) // 命中或者更新缓存
+	remove(entry *cacheEntry) // 删除缓存
+	victim() *cacheEntry      // 选择需要淘汰的缓存
+}
+
+// 淘汰策略使用的缓存状态
+type policyEntry struct {
+	elem   *list.Element // lru/tinylfu链表元素
+	main   bool          // tinylfu是否位于主区
+	index  int           // lfu堆下标
+	freq   uint64        // lfu使用次数
+	access uint64        // lfu最近使用序号
+}
+
+// 根据名称创建淘汰策略，默认为lru
+func newEvictionPolicy(name string, capacity int) evictionPolicy {
+	switch name {
+	case EvictionLFU:
+		return &lfuPolicy{}
+	case EvictionTinyLFU:
+		return newTinyLFUPolicy(capacity)
+	default:
+		return &lruPolicy{ll: list.New()}
+	}
+}
+
+// lru淘汰策略，淘汰最久未使用的缓存
+type lruPolicy struct {
+	ll *list.List // 队首为最近使用
+}
+
+func (p *lruPolicy) record(string) {}
+
+func (p *lruPolicy) add(entry *cacheEntry) {
+	entry.elem = p.ll.PushFront(entry)
+}
+
+func (p *lruPolicy) access(entry *cacheEntry) {
+	p.ll.MoveToFront(entry.elem)
+}
+
+func (p *lruPolicy) remove(entry *cacheEntry) {
+	p.ll.Remove(entry.elem)
+}
+
+func (p *lruPolicy) victim() *cacheEntry {
+	if back := p.ll.Back(); back != nil {
+		return back.Value.(*cacheEntry)
+	}
+	return nil
+}
+
+// lfu淘汰策略，淘汰使用次数最少的缓存，次数相同时淘汰最久未使用的缓存
+type lfuPolicy struct {
+	entries lfuHeap
+	counter uint64
+}
+
+func (p *lfuPolicy) record(string) {}
+
+func (p *lfuPolicy) add(entry *cacheEntry) {
+	p.counter++
+	entry.freq, entry.access = 1, p.counter
+	heap.Push(&p.entries, entry)
+}
+
+func (p *lfuPolicy) access(entry *cacheEntry) {
+	p.counter++
+	entry.freq, entry.access = entry.freq+1, p.counter
+	heap.Fix(&p.entries, entry.index)
+}
+
+func (p *lfuPolicy) remove(entry *cacheEntry) {
+	heap.Remove(&p.entries, entry.index)
+}
+
+func (p *lfuPolicy) victim() *cacheEntry {
+	if len(p.entries) > 0 {
+		return p.entries[0]
+	}
+	return nil
+}
+
+// 按照使用次数以及最近使用序号排序的小顶堆
+type lfuHeap []*cacheEntry
+
+func (h lfuHeap) Len() int { return len(h) }
+
+func (h lfuHeap) Less(i, j int) bool {
+	if h[i].freq != h[j].freq {
+		return h[i].freq < h[j].freq
+	}
+	return h[i].access < h[j].access
+}
+
+func (h lfuHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index, h[j].index = i, j
+}
+
+func (h *lfuHeap) Push(x any) {
+	entry := x.(*cacheEntry)
+	entry.index = len(*h)
+	*h = append(*h, entry)
+}
+
+func (h *lfuHeap) Pop() any {
+	old := *h
+	entry := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return entry
+}
+
+// tinylfu淘汰策略（W-TinyLFU），新数据先进入容量约为1%的窗口区，窗口区最久未使用的数据晋升至主区成为候选
+// 需要淘汰时，候选与主区最久未使用的数据比较访问频率，候选频率更高时淘汰主区数据，否则淘汰候选
+type tinyLFUPolicy struct {
+	window    *list.List  // 窗口区
+	main      *list.List  // 主区
+	candidate *cacheEntry // 最近晋升的候选
+	sketch    *cmSketch   // 访问频率估计
+	capacity  int         // 最大数量，0表示按照当前数量计算窗口区容量
+}
+
+func newTinyLFUPolicy(capacity int) *tinyLFUPolicy {
+	size := capacity
+	if size <= 0 {
+		size = defaultSketchSize
+	}
+	return &tinyLFUPolicy{
+		window:   list.New(),
+		main:     list.New(),
+		sketch:   newCMSketch(size),
+		capacity: capacity,
+	}
+}
+
+func (p *tinyLFUPolicy) record(key string) {
+	p.sketch.add(key)
+}
+
+func (p *tinyLFUPolicy) add(entry *cacheEntry) {
+	entry.main = false
+	entry.elem = p.window.PushFront(entry)
+	if p.window.Len() > p.windowCap() {
+		candidate := p.window.Back().Value.(*cacheEntry)
+		p.window.Remove(candidate.elem)
+		candidate.main = true
+		candidate.elem = p.main.PushFront(candidate)
+		p.candidate = candidate
+	}
+}
+
+func (p *tinyLFUPolicy) access(entry *cacheEntry) {
+	if entry.main {
+		p.main.MoveToFront(entry.elem)
+	} else {
+		p.window.MoveToFront(entry.elem)
+	}
+}
+
+func (p *tinyLFUPolicy) remove(entry *cacheEntry) {
+	if entry == p.candidate {
+		p.candidate = nil
+	}
+	if entry.main {
+		p.main.Remove(entry.elem)
+	} else {
+		p.window.Remove(entry.elem)
+	}
+}
+
+func (p *tinyLFUPolicy) victim() *cacheEntry {
+	back := p.main.Back()
+	if back == nil {
+		if back = p.window.Back(); back != nil {
+			return back.Value.(*cacheEntry)
+		}
+		return nil
+	}
+	victim := back.Value.(*cacheEntry)
+	if candidate := p.candidate; candidate != nil && candidate != victim {
+		p.candidate = nil
+		if p.sketch.estimate(candidate.key) <= p.sketch.estimate(victim.key) {
+			return candidate
+		}
+	}
+	return victim
+}
+
+// 窗口区容量
+func (p *tinyLFUPolicy) windowCap() int {
+	capacity := p.capacity
+	if capacity <= 0 {
+		capacity = p.window.Len() + p.main.Len()
+	}
+	return max(capacity/100, 1)
+}
+
+// Count-Min Sketch，使用4行4位计数器估计访问频率，访问次数达到阈值后计数减半以适应访问模式的变化
+type cmSketch struct {
+	rows      [4][]uint8
+	mask      uint64
+	seed      maphash.Seed
+	additions int
+	resetAt   int
+}
+
+// capacity为缓存容量，每行计数器数量为容量的4倍以降低冲突，访问次数达到容量的10倍时计数减半
+func newCMSketch(capacity int) *cmSketch {
+	size := 16
+	for size < capacity*4 {
+		size <<= 1
+	}
+	s := &cmSketch{mask: uint64(size - 1), seed: maphash.MakeSeed(), resetAt: capacity * 10}
+	for i := range s.rows {
+		s.rows[i] = make([]uint8, size)
+	}
+	return s
+}
+
+func (s *cmSketch) add(key string) {
+	h := maphash.String(s.seed, key)
+	for i := range s.rows {
+		if idx := s.index(h, i); s.rows[i][idx] < 15 {
+			s.rows[i][idx]++
+		}
+	}
+	if s.additions++; s.additions >= s.resetAt {
+		s.reset()
+	}
+}
+
+func (s *cmSketch) estimate(key string) uint8 {
+	h := maphash.String(s.seed, key)
+	var estimate uint8 = 15
+	for i := range s.rows {
+		estimate = min(estimate, s.rows[i][s.index(h, i)])
+	}
+	return estimate
+}
+
+func (s *cmSketch) index(h uint64, i int) uint64 {
+	return (h + uint64(i)*((h>>32)|1)) & s.mask
+}
+
+// 计数减半
+func (s *cmSketch) reset() {
+	for i := range s.rows {
+		for j := range s.rows[i] {
+			s.rows[i][j] >>= 1
+		}
+	}
+	s.additions /= 2
+}
@@ -0,0 +1,188 @@
+package cachex
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-xuan/utilx/errorx"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sync/singleflight"
+)
+
+// ErrNotFound 数据不存在，加载函数返回此错误时可缓存空结果，参考 SetNegativeTTL
+var ErrNotFound = errors.New("cachex: not found")
+
+// DefaultLoadMetrics 默认的缓存加载统计
+var DefaultLoadMetrics = &LoadMetrics{}
+
+// 合并同一key的并发加载
+var loadGroup singleflight.Group
+
+// Loader 数据加载函数，例如查询数据库
+type Loader[T any] func(ctx context.Context) (T, error)
+
+// LoadOption 缓存加载选项
+type LoadOption = func(o *loadOptions)
+
+type loadOptions struct {
+	negativeTTL time.Duration // 空结果缓存时间
+	jitter      float64       // 过期时间随机增加的比例
+	staleTTL    time.Duration // 过期后可返回旧值的时间
+	metrics     *LoadMetrics  // 统计
+}
+
+// SetNegativeTTL 加载函数返回 ErrNotFound 时缓存空结果，避免不存在的数据反复穿透到数据源，默认不缓存
+func SetNegativeTTL(ttl time.Duration) LoadOption {
+	return func(o *loadOptions) {
+		o.negativeTTL = ttl
+	}
+}
+
+// SetJitter 过期时间随机增加[0, ttl*ratio)，避免大量缓存同时过期，例如0.1
+func SetJitter(ratio float64) LoadOption {
+	return func(o *loadOptions) {
+		o.jitter = ratio
+	}
+}
+
+// SetStaleTTL 缓存过期后的stale时间内直接返回旧值，同时在后台刷新（stale-while-revalidate）
+func SetStaleTTL(ttl time.Duration) LoadOption {
+	return func(o *loadOptions) {
+		o.staleTTL = ttl
+	}
+}
+
+// SetLoadMetrics 指定统计，默认为 DefaultLoadMetrics
+func SetLoadMetrics(metrics *LoadMetrics) LoadOption {
+	return func(o *loadOptions) {
+		if metrics != nil {
+			o.metrics = metrics
+		}
+	}
+}
+
+// LoadMetrics 缓存加载统计，并发安全
+type LoadMetrics struct {
+	hits, staleHits, negativeHits, misses, loads, loadErrors, shared atomic.Uint64
+	loadTime                                                         atomic.Int64
+}
+
+// LoadStats 缓存加载统计快照
+type LoadStats struct {
+	Hits         uint64        `json:"hits"`         // 命中次数
+	StaleHits    uint64        `json:"staleHits"`    // 命中已过期旧值的次数
+	NegativeHits uint64        `json:"negativeHits"` // 命中空结果的次数
+	Misses       uint64        `json:"misses"`       // 未命中次数
+	Loads        uint64        `json:"loads"`        // 调用加载函数的次数
+	LoadErrors   uint64        `json:"loadErrors"`   // 加载失败次数，不包括 ErrNotFound
+	Shared       uint64        `json:"shared"`       // 未命中时等待其他并发请求加载结果的次数
+	LoadTime     time.Duration `json:"loadTime"`     // 加载函数总耗时
+}
+
+// HitRatio 命中率
+func (s LoadStats) HitRatio() float64 {
+	return ratio(s.Hits+s.StaleHits+s.NegativeHits, s.Misses)
+}
+
+// Stats 获取统计快照
+func (m *LoadMetrics) Stats() LoadStats {
+	return LoadStats{
+		Hits:         m.hits.Load(),
+		StaleHits:    m.staleHits.Load(),
+		NegativeHits: m.negativeHits.Load(),
+		Misses:       m.misses.Load(),
+		Loads:        m.loads.Load(),
+		LoadErrors:   m.loadErrors.Load(),
+		Shared:       m.shared.Load(),
+		LoadTime:     time.Duration(m.loadTime.Load()),
+	}
+}
+
+// 由 GetOrLoad 写入的缓存
+type loadEntry[T any] struct {
+	Value    T     `json:"value" yaml:"value"`
+	Negative bool  `json:"negative,omitempty" yaml:"negative,omitempty"` // 是否为空结果
+	ExpireAt int64 `json:"expireAt" yaml:"expireAt"`                     // 逻辑过期时间（毫秒时间戳），之后为旧值，0表示不过期
+}
+
+// GetOrLoad 获取缓存，未命中时调用加载函数并写入缓存，适用于任意 Client
+// 1. 同一进程内相同key的并发加载只调用一次加载函数（singleflight）
+// 2. 缓存以包含逻辑过期时间的格式写入，不能通过 Client.Get 直接读取为T
+// 3. ttl小于等于0时不过期，加载失败时不写入缓存
+func GetOrLoad[T any](ctx context.Context, client Client, key string, ttl time.Duration, loader Loader[T], options ...LoadOption) (T, error) {
+	opts := &loadOptions{metrics: DefaultLoadMetrics}
+	for _, option := range options {
+		option(opts)
+	}
+	flight := client.GetConfig().Source + "\n" + client.GetKey(key)
+
+	var entry loadEntry[T]
+	if client.Get(ctx, key, &entry) {
+		if entry.Negative {
+			opts.metrics.negativeHits.Add(1)
+			return entry.Value, ErrNotFound
+		}
+		if entry.ExpireAt == 0 || time.Now().UnixMilli() < entry.ExpireAt {
+			opts.metrics.hits.Add(1)
+			return entry.Value, nil
+		}
+		// 返回旧值并在后台刷新，刷新结果不需要等待
+		opts.metrics.staleHits.Add(1)
+		ctx = context.WithoutCancel(ctx)
+		loadGroup.DoChan(flight, func() (any, error) {
+			return load(ctx, client, key, ttl, loader, opts)
+		})
+		return entry.Value, nil
+	}
+
+	opts.metrics.misses.Add(1)
+	var leader bool // 是否由当前请求调用加载函数
+	result, err, shared := loadGroup.Do(flight, func() (any, error) {
+		leader = true
+		return load(ctx, client, key, ttl, loader, opts)
+	})
+	if shared && !leader {
+		opts.metrics.shared.Add(1)
+	}
+	value, ok := result.(T)
+	if !ok && result != nil && err == nil {
+		err = errorx.Sprintf("unexpected loaded value type for key: %s", key)
+	}
+	return value, err
+}
+
+// 调用加载函数并写入缓存
+func load[T any](ctx context.Context, client Client, key string, ttl time.Duration, loader Loader[T], opts *loadOptions) (T, error) {
+	start := time.Now()
+	value, err := loader(ctx)
+	opts.metrics.loads.Add(1)
+	opts.metrics.loadTime.Add(int64(time.Since(start)))
+
+	entry := loadEntry[T]{Value: value}
+	if errors.Is(err, ErrNotFound) {
+		if opts.negativeTTL <= 0 {
+			return value, err
+		}
+		entry.Negative = true
+		ttl = opts.negativeTTL
+	} else if err != nil {
+		opts.metrics.loadErrors.Add(1)
+		return value, err
+	} else if ttl > 0 {
+		if opts.jitter > 0 {
+			ttl += time.Duration(rand.Float64() * opts.jitter * float64(ttl))
+		}
+		entry.ExpireAt = time.Now().Add(ttl).UnixMilli()
+		ttl += opts.staleTTL
+	}
+	if err = client.Set(ctx, key, entry, ttl); err != nil {
+		log.WithField("key", client.GetKey(key)).WithError(err).Warn("cache loaded value failed")
+	}
+	if entry.Negative {
+		return value, ErrNotFound
+	}
+	return value, nil
+}
@@ -0,0 +1,82 @@
+package cachex
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetOrLoad(t *testing.T) {
+	client, _ := NewLocalClient(&Config{Source: "loader", Marshal: "json", JanitorInterval: -1})
+	defer client.Close()
+	ctx := context.Background()
+	metrics := &LoadMetrics{}
+
+	// 并发未命中时只加载一次
+	var loads atomic.Int32
+	loader := func(ctx context.Context) (int, error) {
+		loads.Add(1)
+		time.Sleep(50 * time.Millisecond)
+		return int(loads.Load()), nil
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if value, err := GetOrLoad(ctx, client, "key", time.Minute, loader, SetLoadMetrics(metrics), SetJitter(0.1)); err != nil || value != 1 {
+				t.Errorf("unexpected value: %d %v", value, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if value, _ := GetOrLoad(ctx, client, "key", time.Minute, loader, SetLoadMetrics(metrics)); value != 1 || loads.Load() != 1 {
+		t.Fatalf("expected cached value, got %d, loads %d", value, loads.Load())
+	}
+	if stats := metrics.Stats(); stats.Loads != 1 || stats.Hits != 1 || stats.Misses+stats.Hits != 11 || stats.Shared != stats.Misses-1 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+
+	// 缓存空结果
+	var notFound atomic.Int32
+	missing := func(ctx context.Context) (*struct{}, error) {
+		notFound.Add(1)
+		return nil, ErrNotFound
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := GetOrLoad(ctx, client, "missing", time.Minute, missing, SetNegativeTTL(time.Second)); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("expected ErrNotFound, got %v", err)
+		}
+	}
+	if notFound.Load() != 1 {
+		t.Fatalf("negative result should be cached, loads %d", notFound.Load())
+	}
+
+	// 加载失败时不缓存
+	failed := func(ctx context.Context) (string, error) { return "", errors.New("db down") }
+	if _, err := GetOrLoad(ctx, client, "failed", time.Minute, failed); err == nil || client.Exist(ctx, "failed") {
+		t.Fatal("failed load should not be cached")
+	}
+
+	// 过期后返回旧值并在后台刷新
+	version := atomic.Int32{}
+	refresh := func(ctx context.Context) (int32, error) { return version.Add(1), nil }
+	if value, _ := GetOrLoad(ctx, client, "stale", 50*time.Millisecond, refresh, SetStaleTTL(time.Minute)); value != 1 {
+		t.Fatalf("unexpected value: %d", value)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if value, _ := GetOrLoad(ctx, client, "stale", 50*time.Millisecond, refresh, SetStaleTTL(time.Minute)); value != 1 {
+		t.Fatalf("expected stale value, got %d", value)
+	}
+	deadline := time.Now().Add(time.Second)
+	for version.Load() < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if value, _ := GetOrLoad(ctx, client, "stale", 50*time.Millisecond, refresh, SetStaleTTL(time.Minute)); value != 2 {
+		t.Fatalf("expected refreshed value, got %d", value)
+	}
+}
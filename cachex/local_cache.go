@@ -0,0 +1,333 @@
+package cachex
+
+import (
+	"sync"
+	"time"
+)
+
+// EvictReason 本地缓存淘汰原因
+type EvictReason string
+
+const (
+	EvictExpired  EvictReason = "expired"  // 过期
+	EvictCapacity EvictReason = "capacity" // 超出容量
+)
+
+const (
+	EvictionLRU     = "lru"     // 淘汰最久未使用
+	EvictionLFU     = "lfu"     // 淘汰使用次数最少
+	EvictionTinyLFU = "tinylfu" // 根据访问频率决定是否接纳新数据，适用于访问频率分布不均的场景
+
+	defaultJanitorInterval = 60 // 默认过期清理间隔（秒）
+)
+
+// EvictFunc 本地缓存淘汰回调，value为序列化后的值（数据结构为空字符串），回调在锁外同步执行
+type EvictFunc func(key, value string, reason EvictReason)
+
+// NewLocalCache 根据配置创建本地缓存，并启动过期清理任务
+func NewLocalCache(config *Config) *LocalCache {
+	c := &LocalCache{
+		items:      make(map[string]*cacheEntry),
+		policy:     newEvictionPolicy(config.Eviction, config.MaxEntries),
+		maxEntries: config.MaxEntries,
+		maxBytes:   config.MaxBytes,
+		onEvict:    config.OnEvict,
+		stop:       make(chan struct{}),
+	}
+	interval := config.JanitorInterval
+	if interval == 0 {
+		interval = defaultJanitorInterval
+	}
+	if interval > 0 {
+		go c.janitor(time.Duration(interval) * time.Second)
+	}
+	return c
+}
+
+// LocalCache 容量有限的本地缓存，并发安全
+// 1. 数量超过 Config.MaxEntries 或者内存超过 Config.MaxBytes 时按照淘汰策略淘汰
+// 2. 过期数据在读取时或者由过期清理任务删除
+type LocalCache struct {
+	mu         sync.Mutex
+	items      map[string]*cacheEntry
+	policy     evictionPolicy
+	maxEntries int   // 最大数量，0表示不限制
+	maxBytes   int64 // 最大内存（key以及value的字节数），0表示不限制
+	bytes      int64 // 当前内存
+	onEvict    EvictFunc
+	stop       chan struct{}
+	once       sync.Once
+}
+
+type cacheEntry struct {
+	key      string
+	value    string    // 字符串值
+	data     any       // 数据结构值，参考 client_local_structure.go，不为nil时value为空
+	bytes    int64     // 占用的内存
+	expireAt time.Time // 过期时间，零值表示不过期
+	policyEntry
+}
+
+func (e *cacheEntry) expired(now time.Time) bool {
+	return !e.expireAt.IsZero() && now.After(e.expireAt)
+}
+
+type evicted struct {
+	key, value string
+	reason     EvictReason
+}
+
+// Get 获取缓存，已过期的缓存将被删除
+func (c *LocalCache) Get(key string) (value string, ok bool) {
+	c.atomic(func(tx *localTx) { value, ok = tx.get(key) })
+	return
+}
+
+// Set 更新缓存，ttl小于等于0时不过期，超出容量时按照淘汰策略淘汰
+// 单个缓存超出最大内存时不写入（同时删除旧值），并以 EvictCapacity 触发淘汰回调
+func (c *LocalCache) Set(key, value string, ttl time.Duration) {
+	c.atomic(func(tx *localTx) { tx.set(key, value, ttl) })
+}
+
+// update 在锁内原子地读取并修改缓存，参考 localTx.update
+func (c *LocalCache) update(key string, create bool, fn func(entry *cacheEntry, created bool) (int64, error)) (err error) {
+	c.atomic(func(tx *localTx) { err = tx.update(key, create, fn) })
+	return
+}
+
+// Expire 更新过期时间，ttl小于等于0时不过期，缓存不存在时返回false
+func (c *LocalCache) Expire(key string, ttl time.Duration) (ok bool) {
+	c.atomic(func(tx *localTx) { ok = tx.expire(key, ttl) })
+	return
+}
+
+// Delete 删除缓存，不会触发淘汰回调
+func (c *LocalCache) Delete(key string) (ok bool) {
+	c.atomic(func(tx *localTx) { ok = tx.delete(key) })
+	return
+}
+
+// DeleteFunc 删除key满足条件的缓存，不会触发淘汰回调，返回删除数量
+func (c *LocalCache) DeleteFunc(match func(key string) bool) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var count int
+	for key, entry := range c.items {
+		if match(key) {
+			c.remove(entry, "")
+			count++
+		}
+	}
+	return count
+}
+
+// atomic 在锁内执行多个操作，淘汰回调在解锁后执行
+func (c *LocalCache) atomic(fn func(tx *localTx)) {
+	tx := &localTx{c: c}
+	defer func() { c.notify(tx.out) }()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	fn(tx)
+}
+
+// 锁内操作，仅在 LocalCache.atomic 中使用
+type localTx struct {
+	c   *LocalCache
+	out []evicted // 被淘汰的缓存
+}
+
+func (tx *localTx) get(key string) (string, bool) {
+	c := tx.c
+	c.policy.record(key)
+	entry, ok := c.items[key]
+	if !ok {
+		return "", false
+	}
+	if entry.expired(time.Now()) {
+		tx.out = append(tx.out, c.remove(entry, EvictExpired))
+		return "", false
+	}
+	c.policy.access(entry)
+	return entry.value, true
+}
+
+func (tx *localTx) set(key, value string, ttl time.Duration) {
+	var expireAt time.Time
+	if ttl > 0 {
+		expireAt = time.Now().Add(ttl)
+	}
+	c := tx.c
+	c.policy.record(key)
+	size := int64(len(key) + len(value))
+	if c.maxBytes > 0 && size > c.maxBytes {
+		// 淘汰其他缓存也无法容纳，直接拒绝写入，并删除旧值避免读取到过期数据
+		if entry, ok := c.items[key]; ok {
+			c.remove(entry, "")
+		}
+		tx.out = append(tx.out, evicted{key: key, value: value, reason: EvictCapacity})
+		return
+	}
+	if entry, ok := c.items[key]; ok {
+		c.bytes += size - entry.bytes
+		entry.value, entry.data, entry.bytes, entry.expireAt = value, nil, size, expireAt
+		c.policy.access(entry)
+	} else {
+		c.add(&cacheEntry{key: key, value: value, bytes: size, expireAt: expireAt}, &tx.out)
+	}
+	c.evict(0, 0, &tx.out)
+}
+
+// update 读取并修改缓存，fn返回内存变化量
+// 1. 缓存不存在或者已过期时，create为true则创建空缓存（created为true），否则entry为nil
+// 2. 新创建的缓存在fn返回错误时删除，数据结构为空时删除缓存，与redis一致
+func (tx *localTx) update(key string, create bool, fn func(entry *cacheEntry, created bool) (int64, error)) error {
+	c := tx.c
+	c.policy.record(key)
+	entry, ok := c.items[key]
+	if ok && entry.expired(time.Now()) {
+		tx.out = append(tx.out, c.remove(entry, EvictExpired))
+		ok = false
+	}
+	if !ok && !create {
+		_, err := fn(nil, false)
+		return err
+	}
+	if ok {
+		c.policy.access(entry)
+	} else {
+		entry = &cacheEntry{key: key, bytes: int64(len(key))}
+		c.add(entry, &tx.out)
+	}
+	delta, err := fn(entry, !ok)
+	entry.bytes += delta
+	c.bytes += delta
+	if (err != nil && !ok) || isEmptyData(entry.data) {
+		c.remove(entry, "")
+	} else if c.maxBytes > 0 && entry.bytes > c.maxBytes {
+		// 数据结构超出最大内存时直接淘汰，避免淘汰全部其他缓存后仍超出容量
+		tx.out = append(tx.out, c.remove(entry, EvictCapacity))
+	}
+	c.evict(0, 0, &tx.out)
+	return err
+}
+
+func (tx *localTx) expire(key string, ttl time.Duration) bool {
+	entry, ok := tx.c.items[key]
+	if !ok || entry.expired(time.Now()) {
+		return false
+	}
+	entry.expireAt = time.Time{}
+	if ttl > 0 {
+		entry.expireAt = time.Now().Add(ttl)
+	}
+	return true
+}
+
+func (tx *localTx) delete(key string) bool {
+	if entry, ok := tx.c.items[key]; ok {
+		tx.c.remove(entry, "")
+		return true
+	}
+	return false
+}
+
+// DeleteExpired 删除全部过期缓存
+func (c *LocalCache) DeleteExpired() {
+	var out []evicted
+	now := time.Now()
+	c.mu.Lock()
+	for _, entry := range c.items {
+		if entry.expired(now) {
+			out = append(out, c.remove(entry, EvictExpired))
+		}
+	}
+	c.mu.Unlock()
+	c.notify(out)
+}
+
+// Len 缓存数量，包括已过期但未删除的缓存
+func (c *LocalCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.items)
+}
+
+// Bytes 缓存占用的内存（key以及value的字节数）
+func (c *LocalCache) Bytes() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.bytes
+}
+
+// Clear 清空缓存，不会触发淘汰回调
+func (c *LocalCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, entry := range c.items {
+		c.remove(entry, "")
+	}
+}
+
+// Close 停止过期清理任务并清空缓存
+func (c *LocalCache) Close() {
+	c.once.Do(func() { close(c.stop) })
+	c.Clear()
+}
+
+// 添加缓存，先淘汰再添加，避免新数据被立即淘汰
+func (c *LocalCache) add(entry *cacheEntry, out *[]evicted) {
+	c.evict(1, entry.bytes, out)
+	c.items[entry.key] = entry
+	c.bytes += entry.bytes
+	c.policy.add(entry)
+}
+
+// 按照淘汰策略淘汰，直到增加指定数量以及内存后不超出容量，增加的内存本身超出最大内存时不淘汰
+func (c *LocalCache) evict(entries int, bytes int64, out *[]evicted) {
+	if c.maxBytes > 0 && bytes > c.maxBytes {
+		return
+	}
+	for c.overflow(entries, bytes) {
+		victim := c.policy.victim()
+		if victim == nil {
+			return
+		}
+		*out = append(*out, c.remove(victim, EvictCapacity))
+	}
+}
+
+// 增加指定数量以及内存后是否超出容量
+func (c *LocalCache) overflow(entries int, bytes int64) bool {
+	return (c.maxEntries > 0 && len(c.items)+entries > c.maxEntries) || (c.maxBytes > 0 && c.bytes+bytes > c.maxBytes)
+}
+
+func (c *LocalCache) remove(entry *cacheEntry, reason EvictReason) evicted {
+	delete(c.items, entry.key)
+	c.bytes -= entry.bytes
+	c.policy.remove(entry)
+	return evicted{key: entry.key, value: entry.value, reason: reason}
+}
+
+// 执行淘汰回调
+func (c *LocalCache) notify(out []evicted) {
+	if c.onEvict == nil {
+		return
+	}
+	for _, e := range out {
+		c.onEvict(e.key, e.value, e.reason)
+	}
+}
+
+// 定时清理过期缓存
+func (c *LocalCache) janitor(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+			c.DeleteExpired()
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package cachex
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestLocalCacheEviction(t *testing.T) {
+	var evicted []string
+	onEvict := func(key, _ string, reason EvictReason) {
+		evicted = append(evicted, fmt.Sprintf("%s:%s", key, reason))
+	}
+
+	// lru 淘汰最久未使用
+	lru := NewLocalCache(&Config{MaxEntries: 2, Eviction: EvictionLRU, OnEvict: onEvict, JanitorInterval: -1})
+	lru.Set("a", "1", 0)
+	lru.Set("b", "2", 0)
+	lru.Get("a")
+	lru.Set("c", "3", 0)
+	if _, ok := lru.Get("b"); ok || fmt.Sprint(evicted) != "[b:capacity]" {
+		t.Fatalf("lru should evict b, evicted: %v", evicted)
+	}
+
+	// lfu 淘汰使用次数最少
+	evicted = nil
+	lfu := NewLocalCache(&Config{MaxEntries: 2, Eviction: EvictionLFU, OnEvict: onEvict, JanitorInterval: -1})
+	lfu.Set("a", "1", 0)
+	lfu.Set("b", "2", 0)
+	lfu.Get("a")
+	lfu.Get("b")
+	lfu.Get("b")
+	lfu.Set("c", "3", 0)
+	lfu.Set("d", "4", 0)
+	if _, ok := lfu.Get("b"); !ok || fmt.Sprint(evicted) != "[a:capacity c:capacity]" {
+		t.Fatalf("lfu should evict a and c, evicted: %v", evicted)
+	}
+
+	// 内存限制
+	evicted = nil
+	bytes := NewLocalCache(&Config{MaxBytes: 10, OnEvict: onEvict, JanitorInterval: -1})
+	bytes.Set("a", "1234", 0)
+	bytes.Set("b", "1234", 0)
+	if bytes.Bytes() != 10 || bytes.Len() != 2 {
+		t.Fatalf("unexpected bytes: %d", bytes.Bytes())
+	}
+	bytes.Set("c", "1", 0)
+	if bytes.Len() != 2 || fmt.Sprint(evicted) != "[a:capacity]" {
+		t.Fatalf("bytes limit should evict a, evicted: %v", evicted)
+	}
+
+	// 超出最大内存的缓存不写入，也不淘汰其他缓存
+	evicted = nil
+	bytes.Set("c", "12345678901", 0)
+	if _, ok := bytes.Get("c"); ok || bytes.Len() != 1 || bytes.Bytes() > 10 || fmt.Sprint(evicted) != "[c:capacity]" {
+		t.Fatalf("oversized value should be rejected, len: %d, evicted: %v", bytes.Len(), evicted)
+	}
+	if _, ok := bytes.Get("b"); !ok {
+		t.Fatal("other entries should not be evicted for an oversized value")
+	}
+}
+
+func TestLocalCacheTinyLFU(t *testing.T) {
+	cache := NewLocalCache(&Config{MaxEntries: 100, Eviction: EvictionTinyLFU, JanitorInterval: -1})
+	// 热点数据多次访问
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("hot%d", i)
+		cache.Set(key, "v", 0)
+		for j := 0; j < 5; j++ {
+			cache.Get(key)
+		}
+	}
+	// 仅访问一次的数据不会淘汰热点数据
+	for i := 0; i < 1000; i++ {
+		cache.Set(fmt.Sprintf("cold%d", i), "v", 0)
+	}
+	var hits int
+	for i := 0; i < 100; i++ {
+		if _, ok := cache.Get(fmt.Sprintf("hot%d", i)); ok {
+			hits++
+		}
+	}
+	if cache.Len() != 100 || hits < 90 {
+		t.Fatalf("tinylfu should keep hot keys, len: %d, hits: %d", cache.Len(), hits)
+	}
+}
+
+func TestLocalCacheJanitor(t *testing.T) {
+	expired := make(chan string, 1)
+	cache := NewLocalCache(&Config{OnEvict: func(key, _ string, reason EvictReason) {
+		if reason == EvictExpired {
+			expired <- key
+		}
+	}})
+	defer cache.Close()
+	cache.Set("key", "value", 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	cache.DeleteExpired()
+	select {
+	case key := <-expired:
+		if key != "key" || cache.Len() != 0 {
+			t.Fatalf("unexpected expired key: %s", key)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expired key not evicted")
+	}
+}
+
+func TestLocalCacheStructureBytes(t *testing.T) {
+	client, _ := NewLocalClient(&Config{JanitorInterval: -1})
+	ctx := context.Background()
+	_, _ = client.HSet(ctx, "h", map[string]string{"ab": "cd"})
+	_, _ = client.RPush(ctx, "l", "xyz")
+	_, _ = client.ZAdd(ctx, "z", Z{Member: "m", Score: 1})
+	if bytes := client.GetClient().Bytes(); bytes != 1+4+1+3+1+1+scoreSize {
+		t.Fatalf("unexpected bytes: %d", bytes)
+	}
+	_, _ = client.HDel(ctx, "h", "ab")
+	_, _ = client.RPop(ctx, "l")
+	_, _ = client.ZRem(ctx, "z", "m")
+	if client.GetClient().Len() != 0 || client.GetClient().Bytes() != 0 {
+		t.Fatalf("empty structures should be deleted, bytes: %d", client.GetClient().Bytes())
+	}
+}
@@ -0,0 +1,234 @@
+package cachex
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-xuan/utilx/errorx"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	ErrNotObtained = errors.New("cachex: lock not obtained") // 等待时间内未获取到锁
+	ErrLockNotHeld = errors.New("cachex: lock not held")     // 锁已过期或者已被释放
+)
+
+const (
+	defaultLockTTL  = 30 * time.Second       // 默认锁过期时间
+	lockBackoffMin  = 10 * time.Millisecond  // 重试加锁的最小间隔
+	lockBackoffMax  = 500 * time.Millisecond // 重试加锁的最大间隔
+	lockRenewFactor = 3                      // 看门狗在过期时间的1/3时续期
+)
+
+// Locker 分布式锁，由缓存客户端实现，key均使用 Config.GetKey 添加前缀
+// 锁以哈希表存储，字段为持有者token，值为重入次数
+type Locker interface {
+	AcquireLock(ctx context.Context, key, token string, ttl time.Duration, reentrant bool) (bool, error) // 加锁，reentrant为true时相同token可重复加锁
+	ReleaseLock(ctx context.Context, key, token string) (bool, error)                                    // 释放锁，重入次数减为0时删除，未持有锁时返回false
+	RenewLock(ctx context.Context, key, token string, ttl time.Duration) (bool, error)                   // 续期，未持有锁时返回false
+}
+
+// LockOption 加锁选项
+type LockOption = func(o *lockOptions)
+
+type lockOptions struct {
+	client   Client        // 缓存客户端
+	wait     time.Duration // 最长等待时间
+	owner    string        // 持有者，不为空时可重入
+	watchdog bool          // 是否自动续期
+}
+
+// SetLockClient 指定缓存客户端，默认为default数据源
+func SetLockClient(client Client) LockOption {
+	return func(o *lockOptions) {
+		o.client = client
+	}
+}
+
+// SetLockWait TryLock 获取锁失败时的最长等待时间，期间按照指数退避重试，默认不等待
+func SetLockWait(wait time.Duration) LockOption {
+	return func(o *lockOptions) {
+		o.wait = wait
+	}
+}
+
+// SetLockOwner 指定持有者，相同持有者可重复加锁（可重入），释放次数与加锁次数相同时才会释放
+func SetLockOwner(owner string) LockOption {
+	return func(o *lockOptions) {
+		o.owner = owner
+	}
+}
+
+// SetLockWatchdog 是否启用看门狗，启用时在释放前自动续期，默认启用
+func SetLockWatchdog(enable bool) LockOption {
+	return func(o *lockOptions) {
+		o.watchdog = enable
+	}
+}
+
+// Lock 加锁，获取失败时按照指数退避重试，直到获取成功或者ctx结束
+func Lock(ctx context.Context, key string, ttl time.Duration, options ...LockOption) (*Lease, error) {
+	return acquireLease(ctx, key, ttl, true, options)
+}
+
+// TryLock 尝试加锁，在 SetLockWait 指定的时间内未获取到锁时返回 ErrNotObtained
+func TryLock(ctx context.Context, key string, ttl time.Duration, options ...LockOption) (*Lease, error) {
+	return acquireLease(ctx, key, ttl, false, options)
+}
+
+func acquireLease(ctx context.Context, key string, ttl time.Duration, block bool, options []LockOption) (*Lease, error) {
+	opts := &lockOptions{watchdog: true}
+	for _, option := range options {
+		option(opts)
+	}
+	if opts.client == nil {
+		opts.client = GetClient()
+	}
+	locker, ok := opts.client.(Locker)
+	if !ok {
+		return nil, errorx.New("cache client does not support lock")
+	}
+	if ttl <= 0 {
+		ttl = defaultLockTTL
+	}
+	token, reentrant := opts.owner, opts.owner != ""
+	if !reentrant {
+		token = uuid.NewString()
+	}
+
+	var deadline <-chan time.Time
+	if !block {
+		timer := time.NewTimer(opts.wait)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+	backoff := lockBackoffMin
+	for {
+		ok, err := locker.AcquireLock(ctx, key, token, ttl, reentrant)
+		if err != nil {
+			return nil, errorx.Wrap(err, "acquire lock failed")
+		} else if ok {
+			return newLease(ctx, locker, key, token, ttl, opts.watchdog), nil
+		} else if !block && opts.wait <= 0 {
+			return nil, ErrNotObtained
+		}
+		// 随机化重试间隔，避免多个等待者同时重试
+		timer := time.NewTimer(backoff/2 + rand.N(backoff/2+1))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			if block {
+				return nil, errorx.Wrap(ctx.Err(), "acquire lock failed")
+			}
+			return nil, ErrNotObtained
+		case <-deadline:
+			timer.Stop()
+			return nil, ErrNotObtained
+		case <-timer.C:
+		}
+		backoff = min(backoff*2, lockBackoffMax)
+	}
+}
+
+// Lease 锁的持有凭证
+type Lease struct {
+	locker   Locker
+	key      string
+	token    string
+	ttl      time.Duration
+	cancel   context.CancelFunc // 停止看门狗
+	stopped  sync.WaitGroup
+	lost     chan struct{}
+	released atomic.Bool
+}
+
+func newLease(ctx context.Context, locker Locker, key, token string, ttl time.Duration, watchdog bool) *Lease {
+	lease := &Lease{
+		locker: locker,
+		key:    key,
+		token:  token,
+		ttl:    ttl,
+		cancel: func() {},
+		lost:   make(chan struct{}),
+	}
+	if watchdog {
+		var watchCtx context.Context
+		watchCtx, lease.cancel = context.WithCancel(context.WithoutCancel(ctx))
+		lease.stopped.Add(1)
+		go lease.watchdog(watchCtx)
+	}
+	return lease
+}
+
+// Key 锁的key（不含前缀）
+func (l *Lease) Key() string {
+	return l.key
+}
+
+// Token 持有者token
+func (l *Lease) Token() string {
+	return l.token
+}
+
+// Lost 看门狗续期时发现锁已丢失（例如过期后被其他持有者获取）时关闭
+func (l *Lease) Lost() <-chan struct{} {
+	return l.lost
+}
+
+// Renew 手动续期，锁已丢失时返回 ErrLockNotHeld
+func (l *Lease) Renew(ctx context.Context) error {
+	ok, err := l.locker.RenewLock(ctx, l.key, l.token, l.ttl)
+	if err != nil {
+		return errorx.Wrap(err, "renew lock failed")
+	} else if !ok {
+		return ErrLockNotHeld
+	}
+	return nil
+}
+
+// Unlock 停止看门狗并释放锁，通过token校验仅释放自身持有的锁，锁已丢失或者重复释放时返回 ErrLockNotHeld
+func (l *Lease) Unlock(ctx context.Context) error {
+	if !l.released.CompareAndSwap(false, true) {
+		return ErrLockNotHeld
+	}
+	l.cancel()
+	l.stopped.Wait()
+	ok, err := l.locker.ReleaseLock(ctx, l.key, l.token)
+	if err != nil {
+		return errorx.Wrap(err, "release lock failed")
+	} else if !ok {
+		return ErrLockNotHeld
+	}
+	return nil
+}
+
+// 看门狗，定时续期直到释放或者锁丢失
+func (l *Lease) watchdog(ctx context.Context) {
+	defer l.stopped.Done()
+	ticker := time.NewTicker(max(l.ttl/lockRenewFactor, time.Millisecond))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ok, err := l.locker.RenewLock(ctx, l.key, l.token, l.ttl)
+			if err != nil {
+				if ctx.Err() == nil {
+					log.WithField("key", l.key).WithError(err).Warn("renew lock failed")
+				}
+				continue
+			}
+			if !ok {
+				log.WithField("key", l.key).Warn("lock lost")
+				close(l.lost)
+				return
+			}
+		}
+	}
+}
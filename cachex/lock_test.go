@@ -0,0 +1,99 @@
+package cachex
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLock(t *testing.T) {
+	for _, driver := range []string{"local", "memredis"} {
+		t.Run(driver, func(t *testing.T) {
+			client, err := NewClient(&Config{Source: driver, Driver: driver, Prefix: "test:", Marshal: "json"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer client.Close()
+			testLock(t, client)
+		})
+	}
+}
+
+func testLock(t *testing.T, client Client) {
+	ctx := context.Background()
+	use := SetLockClient(client)
+
+	lease, err := Lock(ctx, "job", time.Second, use)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = TryLock(ctx, "job", time.Second, use); !errors.Is(err, ErrNotObtained) {
+		t.Fatalf("expected ErrNotObtained, got %v", err)
+	}
+	// 等待期间释放后获取成功
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_ = lease.Unlock(ctx)
+	}()
+	other, err := TryLock(ctx, "job", time.Second, use, SetLockWait(time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = lease.Unlock(ctx); !errors.Is(err, ErrLockNotHeld) {
+		t.Fatalf("expected ErrLockNotHeld for repeated unlock, got %v", err)
+	}
+	if err = other.Unlock(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// 可重入
+	first, err := Lock(ctx, "reentrant", time.Second, use, SetLockOwner("worker-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := TryLock(ctx, "reentrant", time.Second, use, SetLockOwner("worker-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = TryLock(ctx, "reentrant", time.Second, use, SetLockOwner("worker-2")); !errors.Is(err, ErrNotObtained) {
+		t.Fatalf("expected ErrNotObtained for other owner, got %v", err)
+	}
+	_ = second.Unlock(ctx)
+	if !client.Exist(ctx, "reentrant") {
+		t.Fatal("reentrant lock should be held until all leases are unlocked")
+	}
+	_ = first.Unlock(ctx)
+	if client.Exist(ctx, "reentrant") {
+		t.Fatal("reentrant lock should be released")
+	}
+
+	// 看门狗自动续期
+	watched, err := Lock(ctx, "watched", 100*time.Millisecond, use)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(300 * time.Millisecond)
+	if _, err = TryLock(ctx, "watched", time.Second, use); !errors.Is(err, ErrNotObtained) {
+		t.Fatalf("watchdog should keep the lock, got %v", err)
+	}
+	// 锁被删除后看门狗通知丢失
+	client.Delete(ctx, "watched")
+	select {
+	case <-watched.Lost():
+	case <-time.After(time.Second):
+		t.Fatal("lease should be lost")
+	}
+	if err = watched.Unlock(ctx); !errors.Is(err, ErrLockNotHeld) {
+		t.Fatalf("expected ErrLockNotHeld, got %v", err)
+	}
+
+	// 未启用看门狗时过期释放
+	if _, err = Lock(ctx, "expiring", 50*time.Millisecond, use, SetLockWatchdog(false)); err != nil {
+		t.Fatal(err)
+	}
+	if lease, err = TryLock(ctx, "expiring", time.Second, use, SetLockWait(time.Second)); err != nil {
+		t.Fatalf("expired lock should be obtained, got %v", err)
+	}
+	_ = lease.Unlock(ctx)
+}
@@ -0,0 +1,91 @@
+package cachex
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-xuan/utilx/errorx"
+)
+
+// RateLimitStore 限流存储，由缓存客户端实现，key均使用 Config.GetKey 添加前缀
+type RateLimitStore interface {
+	// AllowTokenBucket 令牌桶，每秒生成rate个令牌，最多积累burst个，请求消耗n个令牌
+	AllowTokenBucket(ctx context.Context, key string, rate float64, burst, n int64) (*RateResult, error)
+	// AllowSlidingWindow 滑动窗口，任意window时长内最多limit个请求，内存占用与limit成正比
+	AllowSlidingWindow(ctx context.Context, key string, limit int64, window time.Duration, n int64) (*RateResult, error)
+}
+
+// RateResult 限流结果
+type RateResult struct {
+	Allowed    bool          `json:"allowed"`    // 是否允许
+	Limit      int64         `json:"limit"`      // 上限（令牌桶容量或者窗口内请求数）
+	Remaining  int64         `json:"remaining"`  // 剩余可用数量
+	RetryAfter time.Duration `json:"retryAfter"` // 被拒绝时建议的重试等待时间
+}
+
+// RateLimiter 限流器
+type RateLimiter interface {
+	Allow(ctx context.Context, key string) (*RateResult, error)           // 请求1次
+	AllowN(ctx context.Context, key string, n int64) (*RateResult, error) // 请求n次
+}
+
+// NewTokenBucketLimiter 创建令牌桶限流器，允许突发burst个请求，长期速率为每秒rate个请求
+func NewTokenBucketLimiter(client Client, rate float64, burst int64) (RateLimiter, error) {
+	store, ok := client.(RateLimitStore)
+	if !ok {
+		return nil, errorx.New("cache client does not support rate limit")
+	} else if rate <= 0 || burst <= 0 {
+		return nil, errorx.New("token bucket rate and burst must be positive")
+	}
+	return &tokenBucketLimiter{store: store, rate: rate, burst: burst}, nil
+}
+
+// NewSlidingWindowLimiter 创建滑动窗口限流器，任意window时长内最多limit个请求
+func NewSlidingWindowLimiter(client Client, limit int64, window time.Duration) (RateLimiter, error) {
+	store, ok := client.(RateLimitStore)
+	if !ok {
+		return nil, errorx.New("cache client does not support rate limit")
+	} else if limit <= 0 || window <= 0 {
+		return nil, errorx.New("sliding window limit and window must be positive")
+	}
+	return &slidingWindowLimiter{store: store, limit: limit, window: window}, nil
+}
+
+type tokenBucketLimiter struct {
+	store RateLimitStore
+	rate  float64
+	burst int64
+}
+
+func (l *tokenBucketLimiter) Allow(ctx context.Context, key string) (*RateResult, error) {
+	return l.AllowN(ctx, key, 1)
+}
+
+func (l *tokenBucketLimiter) AllowN(ctx context.Context, key string, n int64) (*RateResult, error) {
+	if n > l.burst {
+		return nil, errorx.Sprintf("request tokens %d exceed burst %d", n, l.burst)
+	}
+	return l.store.AllowTokenBucket(ctx, key, l.rate, l.burst, n)
+}
+
+type slidingWindowLimiter struct {
+	store  RateLimitStore
+	limit  int64
+	window time.Duration
+}
+
+func (l *slidingWindowLimiter) Allow(ctx context.Context, key string) (*RateResult, error) {
+	return l.AllowN(ctx, key, 1)
+}
+
+func (l *slidingWindowLimiter) AllowN(ctx context.Context, key string, n int64) (*RateResult, error) {
+	if n > l.limit {
+		return nil, errorx.Sprintf("request count %d exceed limit %d", n, l.limit)
+	}
+	return l.store.AllowSlidingWindow(ctx, key, l.limit, l.window, n)
+}
+
+// 令牌桶充满所需的时间（毫秒），作为令牌桶的过期时间
+func tokenBucketTTL(rate float64, burst int64) int64 {
+	return int64(float64(burst)*1000/rate) + 1000
+}
@@ -0,0 +1,88 @@
+package cachex
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	for _, driver := range []string{"local", "memredis"} {
+		t.Run(driver, func(t *testing.T) {
+			client, err := NewClient(&Config{Source: driver, Driver: driver, Prefix: "test:", Marshal: "json"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer client.Close()
+			testTokenBucket(t, client)
+			testSlidingWindow(t, client)
+		})
+	}
+}
+
+func testTokenBucket(t *testing.T, client Client) {
+	ctx := context.Background()
+	limiter, err := NewTokenBucketLimiter(client, 10, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := int64(0); i < 3; i++ {
+		result, err := limiter.Allow(ctx, "bucket")
+		if err != nil {
+			t.Fatal(err)
+		} else if !result.Allowed || result.Remaining != 2-i || result.Limit != 3 {
+			t.Fatalf("request %d: unexpected result %+v", i, result)
+		}
+	}
+	result, err := limiter.Allow(ctx, "bucket")
+	if err != nil {
+		t.Fatal(err)
+	} else if result.Allowed || result.RetryAfter <= 0 || result.RetryAfter > 100*time.Millisecond {
+		t.Fatalf("expected rejection with retry after, got %+v", result)
+	}
+	// 其他key不受影响
+	if result, err = limiter.Allow(ctx, "other"); err != nil || !result.Allowed {
+		t.Fatalf("expected other key allowed, got %+v, %v", result, err)
+	}
+	// 等待补充令牌
+	time.Sleep(result.RetryAfter + 150*time.Millisecond)
+	if result, err = limiter.AllowN(ctx, "bucket", 1); err != nil || !result.Allowed {
+		t.Fatalf("expected allowed after refill, got %+v, %v", result, err)
+	}
+	if _, err = limiter.AllowN(ctx, "bucket", 4); err == nil {
+		t.Fatal("expected error when n exceeds burst")
+	}
+}
+
+func testSlidingWindow(t *testing.T, client Client) {
+	ctx := context.Background()
+	window := 200 * time.Millisecond
+	limiter, err := NewSlidingWindowLimiter(client, 3, window)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := limiter.AllowN(ctx, "window", 2)
+	if err != nil || !result.Allowed || result.Remaining != 1 {
+		t.Fatalf("unexpected result %+v, %v", result, err)
+	}
+	if result, err = limiter.AllowN(ctx, "window", 2); err != nil || result.Allowed || result.Remaining != 1 {
+		t.Fatalf("expected rejection, got %+v, %v", result, err)
+	} else if result.RetryAfter <= 0 || result.RetryAfter > window {
+		t.Fatalf("unexpected retry after %s", result.RetryAfter)
+	}
+	if result, err = limiter.Allow(ctx, "window"); err != nil || !result.Allowed || result.Remaining != 0 {
+		t.Fatalf("unexpected result %+v, %v", result, err)
+	}
+	if result, err = limiter.Allow(ctx, "window"); err != nil || result.Allowed {
+		t.Fatalf("expected rejection, got %+v, %v", result, err)
+	}
+	// 窗口滑过后恢复
+	time.Sleep(window + 50*time.Millisecond)
+	if result, err = limiter.AllowN(ctx, "window", 3); err != nil || !result.Allowed {
+		t.Fatalf("expected allowed after window, got %+v, %v", result, err)
+	}
+
+	if _, err = NewSlidingWindowLimiter(client, 0, window); err == nil {
+		t.Fatal("expected error for non-positive limit")
+	}
+}
@@ -0,0 +1,100 @@
+package cachex
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrNil        = errors.New("cachex: nil")                                                     // key或者成员不存在
+	ErrWrongType  = errors.New("cachex: operation against a key holding the wrong kind of value") // key的数据类型与操作不匹配
+	ErrNotInteger = errors.New("cachex: value is not an integer")                                 // 计数器的值不是整数
+)
+
+// Counter 计数器，计数器的值以字符串存储，可通过 Client.GetString 读取
+type Counter interface {
+	Incr(ctx context.Context, key string) (int64, error)                // 加1，key不存在时从0开始
+	IncrBy(ctx context.Context, key string, value int64) (int64, error) // 增加指定值
+	Decr(ctx context.Context, key string) (int64, error)                // 减1
+	DecrBy(ctx context.Context, key string, value int64) (int64, error) // 减少指定值
+}
+
+// Hash 哈希表
+type Hash interface {
+	HSet(ctx context.Context, key string, values map[string]string) (int64, error) // 设置字段，返回新增字段数量
+	HGet(ctx context.Context, key, field string) (string, error)                   // 获取字段，字段不存在时返回 ErrNil
+	HGetAll(ctx context.Context, key string) (map[string]string, error)            // 获取全部字段
+	HDel(ctx context.Context, key string, fields ...string) (int64, error)         // 删除字段，返回删除数量
+	HLen(ctx context.Context, key string) (int64, error)                           // 字段数量
+}
+
+// List 列表
+type List interface {
+	LPush(ctx context.Context, key string, values ...string) (int64, error)      // 从头部插入，返回列表长度
+	RPush(ctx context.Context, key string, values ...string) (int64, error)      // 从尾部插入，返回列表长度
+	LPop(ctx context.Context, key string) (string, error)                        // 从头部弹出，列表为空时返回 ErrNil
+	RPop(ctx context.Context, key string) (string, error)                        // 从尾部弹出，列表为空时返回 ErrNil
+	LRange(ctx context.Context, key string, start, stop int64) ([]string, error) // 获取下标范围内的元素，负数表示倒数
+	LLen(ctx context.Context, key string) (int64, error)                         // 列表长度
+}
+
+// Set 集合
+type Set interface {
+	SAdd(ctx context.Context, key string, members ...string) (int64, error) // 添加成员，返回新增数量
+	SRem(ctx context.Context, key string, members ...string) (int64, error) // 删除成员，返回删除数量
+	SMembers(ctx context.Context, key string) ([]string, error)             // 全部成员，顺序不确定
+	SIsMember(ctx context.Context, key, member string) (bool, error)        // 是否为成员
+	SCard(ctx context.Context, key string) (int64, error)                   // 成员数量
+}
+
+// Z 有序集合成员
+type Z struct {
+	Member string  `json:"member"` // 成员
+	Score  float64 `json:"score"`  // 分数
+}
+
+// SortedSet 有序集合，成员按照分数升序排列，分数相同时按照成员字典序排列
+type SortedSet interface {
+	ZAdd(ctx context.Context, key string, members ...Z) (int64, error)                          // 添加或者更新成员，返回新增数量
+	ZIncrBy(ctx context.Context, key string, increment float64, member string) (float64, error) // 增加成员分数，返回新的分数
+	ZRem(ctx context.Context, key string, members ...string) (int64, error)                     // 删除成员，返回删除数量
+	ZScore(ctx context.Context, key, member string) (float64, error)                            // 成员分数，成员不存在时返回 ErrNil
+	ZRangeByScore(ctx context.Context, key string, min, max float64) ([]Z, error)               // 分数在[min, max]范围内的成员，按照分数升序
+	ZRevRange(ctx context.Context, key string, start, stop int64) ([]Z, error)                  // 按照分数降序获取排名范围内的成员，例如排行榜
+	ZCard(ctx context.Context, key string) (int64, error)                                       // 成员数量
+}
+
+// Structure 全部数据结构操作，key均使用 Config.GetKey 添加前缀
+type Structure interface {
+	Counter
+	Hash
+	List
+	Set
+	SortedSet
+}
+
+// GetStructure 获取客户端的数据结构操作，客户端不支持时panic
+func GetStructure(source ...string) Structure {
+	structure, ok := GetClient(source...).(Structure)
+	if !ok {
+		panic("cache client does not support data structures")
+	}
+	return structure
+}
+
+// 将负数下标转换为正数下标并限制在[0, n)范围内，范围为空时返回false
+func normalizeRange(start, stop int64, n int) (int, int, bool) {
+	length := int64(n)
+	if start < 0 {
+		start += length
+	}
+	if stop < 0 {
+		stop += length
+	}
+	start = max(start, 0)
+	stop = min(stop, length-1)
+	if start > stop || start >= length {
+		return 0, 0, false
+	}
+	return int(start), int(stop), true
+}
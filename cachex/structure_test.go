@@ -0,0 +1,130 @@
+package cachex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+)
+
+func TestStructure(t *testing.T) {
+	for _, driver := range []string{"local", "memredis", "multilevel"} {
+		t.Run(driver, func(t *testing.T) {
+			client, err := NewClient(&Config{Source: driver, Driver: driver, Remote: "memredis", Prefix: "test:", Marshal: "json"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer client.Close()
+			structure, ok := client.(Structure)
+			if !ok {
+				t.Fatalf("%T does not implement Structure", client)
+			}
+			testStructure(t, client, structure)
+		})
+	}
+}
+
+func testStructure(t *testing.T, client Client, s Structure) {
+	ctx := context.Background()
+	check := func(got, expected any, err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(expected) {
+			t.Fatalf("expected %v, got %v", expected, got)
+		}
+	}
+
+	// 计数器，可通过 GetString 读取
+	n, err := s.Incr(ctx, "counter")
+	check(n, 1, err)
+	n, err = s.IncrBy(ctx, "counter", 10)
+	check(n, 11, err)
+	n, err = s.DecrBy(ctx, "counter", 5)
+	check(n, 6, err)
+	check(client.GetString(ctx, "counter"), "6", nil)
+	if err = client.Set(ctx, "string", "value", 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = s.Incr(ctx, "string"); !errors.Is(err, ErrNotInteger) {
+		t.Fatalf("expected ErrNotInteger, got %v", err)
+	}
+
+	// 哈希表
+	n, err = s.HSet(ctx, "hash", map[string]string{"a": "1", "b": "2"})
+	check(n, 2, err)
+	n, err = s.HSet(ctx, "hash", map[string]string{"b": "3", "c": "4"})
+	check(n, 1, err)
+	value, err := s.HGet(ctx, "hash", "b")
+	check(value, "3", err)
+	if _, err = s.HGet(ctx, "hash", "missing"); !errors.Is(err, ErrNil) {
+		t.Fatalf("expected ErrNil, got %v", err)
+	}
+	n, err = s.HDel(ctx, "hash", "a", "missing")
+	check(n, 1, err)
+	all, err := s.HGetAll(ctx, "hash")
+	check(all, map[string]string{"b": "3", "c": "4"}, err)
+	n, err = s.HLen(ctx, "hash")
+	check(n, 2, err)
+	if _, err = s.LPush(ctx, "hash", "x"); !errors.Is(err, ErrWrongType) {
+		t.Fatalf("expected ErrWrongType, got %v", err)
+	}
+
+	// 列表
+	n, err = s.RPush(ctx, "list", "b", "c")
+	check(n, 2, err)
+	n, err = s.LPush(ctx, "list", "a", "z")
+	check(n, 4, err)
+	items, err := s.LRange(ctx, "list", 0, -1)
+	check(items, []string{"z", "a", "b", "c"}, err)
+	value, err = s.LPop(ctx, "list")
+	check(value, "z", err)
+	value, err = s.RPop(ctx, "list")
+	check(value, "c", err)
+	items, err = s.LRange(ctx, "list", -1, 10)
+	check(items, []string{"b"}, err)
+	n, err = s.LLen(ctx, "list")
+	check(n, 2, err)
+	_, _ = s.LPop(ctx, "list")
+	_, _ = s.LPop(ctx, "list")
+	if _, err = s.RPop(ctx, "list"); !errors.Is(err, ErrNil) {
+		t.Fatalf("expected ErrNil, got %v", err)
+	}
+	if client.Exist(ctx, "list") {
+		t.Fatal("empty list should be deleted")
+	}
+
+	// 集合
+	n, err = s.SAdd(ctx, "set", "a", "b", "a")
+	check(n, 2, err)
+	n, err = s.SRem(ctx, "set", "b", "c")
+	check(n, 1, err)
+	ok, err := s.SIsMember(ctx, "set", "a")
+	check(ok, true, err)
+	members, err := s.SMembers(ctx, "set")
+	sort.Strings(members)
+	check(members, []string{"a"}, err)
+	n, err = s.SCard(ctx, "set")
+	check(n, 1, err)
+
+	// 有序集合
+	n, err = s.ZAdd(ctx, "rank", Z{Member: "a", Score: 3}, Z{Member: "b", Score: 1}, Z{Member: "c", Score: 2})
+	check(n, 3, err)
+	score, err := s.ZIncrBy(ctx, "rank", 5, "b")
+	check(score, 6, err)
+	score, err = s.ZScore(ctx, "rank", "c")
+	check(score, 2, err)
+	zs, err := s.ZRangeByScore(ctx, "rank", 2, 10)
+	check(zs, []Z{{"c", 2}, {"a", 3}, {"b", 6}}, err)
+	zs, err = s.ZRevRange(ctx, "rank", 0, 1)
+	check(zs, []Z{{"b", 6}, {"a", 3}}, err)
+	n, err = s.ZRem(ctx, "rank", "a")
+	check(n, 1, err)
+	n, err = s.ZCard(ctx, "rank")
+	check(n, 2, err)
+	if _, err = s.ZScore(ctx, "rank", "a"); !errors.Is(err, ErrNil) {
+		t.Fatalf("expected ErrNil, got %v", err)
+	}
+}
@@ -12,7 +12,7 @@ import (
 // 配置器的工作流程：
 // 1. 调用Readers()获取配置读取器列表
 // 2. 按顺序使用读取器读取配置数据
-// 3. 调用Valid()检查读取的配置是否有效
+// 3. 根据validate标签校验配置（参考 ValidateTag），并调用Valid()检查读取的配置是否有效
 // 4. 配置有效则调用Execute()执行相关的业务逻辑
 type Configurator interface {
 	// Readers 获取配置读取器列表
@@ -28,51 +28,91 @@ type Configurator interface {
 	Execute() error
 }
 
-// LoadConfigurator 加载配置器
+// LoadConfigurator 加载配置器，配置监听添加到默认的配置监听集合
 func LoadConfigurator(configurator Configurator) error {
+	return defaultWatchers.LoadConfigurator(configurator)
+}
+
+// LoadConfiguratorWith 加载配置器，并使用指定函数代替配置器的Execute方法，配置监听添加到默认的配置监听集合
+func LoadConfiguratorWith(configurator Configurator, execute func() error) error {
+	return defaultWatchers.LoadConfiguratorWith(configurator, execute)
+}
+
+// LoadConfigurator 加载配置器，配置监听添加到当前配置监听集合
+func (w *Watchers) LoadConfigurator(configurator Configurator) error {
 	if configurator == nil {
 		return nil
 	}
+	return w.LoadConfiguratorWith(configurator, configurator.Execute)
+}
 
+// LoadConfiguratorWith 加载配置器，并使用指定函数代替配置器的Execute方法，配置监听添加到当前配置监听集合
+func (w *Watchers) LoadConfiguratorWith(configurator Configurator, execute func() error) error {
 	logger := log.WithField("configurator", reflectx.TypeOf(configurator).String())
 
 	// 读取配置器
-	location, err := ReadConfigurator(configurator)
+	reader, location, err := readConfigurator(configurator)
 	logger = logger.WithField("location", location)
 	if err != nil {
 		logger.WithError(err).Warn("read configurator failed")
 		return errorx.Wrap(err, "read configurator failed")
 	}
+	// 记录各字段的最终来源
+	if or, ok := reader.(OriginReader); ok && len(or.Origins()) > 0 {
+		logger = logger.WithField("origins", or.Origins())
+	}
 
 	// 执行配置器逻辑
-	if err = configurator.Execute(); err != nil {
+	if err = execute(); err != nil {
 		logger.WithError(err).Warn("execute configurator failed")
 		return errorx.Wrap(err, "execute configurator failed")
 	}
+	// 支持热加载的配置器监听配置变更
+	if err = w.watch(configurator, reader); err != nil {
+		logger.WithError(err).Warn("watch configurator failed")
+	}
 	logger.Info("load configurator success")
 	return nil
 }
 
 // ReadConfigurator 读取配置器，返回配置文件位置
 func ReadConfigurator(configurator Configurator) (string, error) {
+	_, location, err := readConfigurator(configurator)
+	return location, err
+}
+
+// 读取配置器，返回生效的读取器以及配置文件位置
+func readConfigurator(configurator Configurator) (Reader, string, error) {
 	if configurator == nil {
-		return "nil", errorx.New("configurator is nil")
+		return nil, "nil", errorx.New("configurator is nil")
 	} else if configurator.Valid() {
-		return "self", nil
+		// 代码中构建的配置同样需要根据validate标签校验
+		if fields := validateWithReader(configurator, nil); len(fields) > 0 {
+			return nil, "self", &ValidationError{Fields: fields}
+		}
+		return nil, "self", nil
 	}
 
 	// 获取配置读取器
 	readers := configurator.Readers()
 	if len(readers) == 0 {
-		return "", errorx.New("the configurator's reader is empty")
+		return nil, "", errorx.New("the configurator's reader is empty")
 	}
-	// 按照读取器的先后顺序依次读取配置
+	// 按照读取器的先后顺序依次读取配置，读取后先根据validate标签校验，再调用Valid()进行额外校验
+	// 读取成功但校验失败时直接返回错误，不再回退到后续读取器（例如默认值），避免错误配置被静默忽略
 	var locations []string
 	for _, reader := range readers {
-		locations = append(locations, reader.Location())
-		if err := ReaderRead(reader, configurator); err == nil && configurator.Valid() {
-			return reader.Location(), nil
+		location := reader.Location()
+		locations = append(locations, location)
+		if err := ReaderRead(reader, configurator); err != nil {
+			continue
+		}
+		if fields := validateWithReader(configurator, reader); len(fields) > 0 {
+			return nil, location, &ValidationError{Fields: fields}
+		}
+		if configurator.Valid() {
+			return reader, location, nil
 		}
 	}
-	return strings.Join(locations, ","), errorx.New("no available reader")
+	return nil, strings.Join(locations, ","), errorx.New("no available reader")
 }
@@ -1,8 +1,13 @@
 package configx
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 type test struct {
@@ -35,3 +40,175 @@ func TestConfigurator(t *testing.T) {
 	}
 	fmt.Println("after execute :", config)
 }
+
+type reloadTest struct {
+	Level   string `json:"level"`
+	changes chan string
+	readers []Reader
+}
+
+func (t *reloadTest) Valid() bool {
+	return t.Level != ""
+}
+
+func (t *reloadTest) Readers() []Reader {
+	return t.readers
+}
+
+func (t *reloadTest) Execute() error {
+	return nil
+}
+
+func (t *reloadTest) OnChange(old, updated Configurator) error {
+	updated.(*reloadTest).changes = t.changes
+	t.changes <- old.(*reloadTest).Level + "->" + updated.(*reloadTest).Level
+	return nil
+}
+
+func TestReloadConfigurator(t *testing.T) {
+	SetWatchInterval(10 * time.Millisecond)
+	defer StopWatchers()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "reload.json")
+	_ = os.WriteFile(path, []byte(`{"level":"info"}`), 0644)
+	reader := &FileReader{Dir: dir, Name: "reload.json"}
+	config := &reloadTest{changes: make(chan string, 1)}
+	if err := reader.Read(config); err != nil {
+		t.Fatal(err)
+	}
+	if err := defaultWatchers.watch(config, reader); err != nil {
+		t.Fatal(err)
+	}
+
+	// 并发读取，监听协程清除缓存时不应产生数据竞争
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				_ = reader.Read(&reloadTest{})
+			}
+		}
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	_ = os.WriteFile(path, []byte(`{"level":"debug"}`), 0644)
+	select {
+	case change := <-config.changes:
+		if change != "info->debug" {
+			t.Errorf("unexpected change: %s", change)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("reload timeout")
+	}
+	if config.Level != "info" {
+		t.Errorf("previous config should not be modified, got %s", config.Level)
+	}
+}
+
+func TestWatchersIsolated(t *testing.T) {
+	SetWatchInterval(10 * time.Millisecond)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "reload.json")
+	_ = os.WriteFile(path, []byte(`{"level":"info"}`), 0644)
+
+	// 不同集合中同一类型、同一位置的监听互不替换，停止一个集合不影响其他集合
+	w1, w2 := NewWatchers(), NewWatchers()
+	defer w2.Stop()
+	c1 := &reloadTest{changes: make(chan string, 1), readers: []Reader{&FileReader{Dir: dir, Name: "reload.json"}}}
+	c2 := &reloadTest{changes: make(chan string, 1), readers: []Reader{&FileReader{Dir: dir, Name: "reload.json"}}}
+	if err := w1.LoadConfigurator(c1); err != nil {
+		t.Fatal(err)
+	}
+	if err := w2.LoadConfigurator(c2); err != nil {
+		t.Fatal(err)
+	}
+	w1.Stop()
+
+	time.Sleep(20 * time.Millisecond)
+	_ = os.WriteFile(path, []byte(`{"level":"debug"}`), 0644)
+	select {
+	case <-c2.changes:
+	case <-time.After(time.Second):
+		t.Fatal("watcher of the running set should reload")
+	}
+	select {
+	case change := <-c1.changes:
+		t.Errorf("stopped watcher should not reload, got %s", change)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+type validateTest struct {
+	Host    string   `json:"host" validate:"required"`
+	Port    int      `json:"port" validate:"min=1,max=65535"`
+	Mode    string   `json:"mode" validate:"oneof=single cluster"`
+	Url     string   `json:"url" validate:"url"`
+	Address string   `json:"address" validate:"hostport"`
+	Timeout string   `json:"timeout" validate:"duration"`
+	Tags    []string `json:"tags" validate:"max=2"`
+	readers []Reader
+}
+
+func (t *validateTest) Valid() bool {
+	return t.Host != ""
+}
+
+func (t *validateTest) Readers() []Reader {
+	return t.readers
+}
+
+func (t *validateTest) Execute() error {
+	return nil
+}
+
+func TestValidate(t *testing.T) {
+	valid := &validateTest{Host: "localhost", Port: 8080, Mode: "single", Url: "http://localhost:9200",
+		Address: "127.0.0.1:6379,127.0.0.1:6380", Timeout: "10s", Tags: []string{"a"}}
+	if err := Validate(valid); err != nil {
+		t.Errorf("expected valid, got %v", err)
+	}
+
+	invalid := &validateTest{Port: 70000, Mode: "other", Url: "localhost", Address: "localhost", Timeout: "10",
+		Tags: []string{"a", "b", "c"}}
+	var validationErr *ValidationError
+	if err := Validate(invalid); !errors.As(err, &validationErr) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	var fields []string
+	for _, field := range validationErr.Fields {
+		fields = append(fields, field.Field)
+	}
+	if expect := "host,port,mode,url,address,timeout,tags"; strings.Join(fields, ",") != expect {
+		t.Errorf("expected invalid fields %s, got %v", expect, fields)
+	}
+
+	// 读取器读取后校验失败，错误中包含字段来源
+	dir := t.TempDir()
+	_ = os.WriteFile(filepath.Join(dir, "validate.json"), []byte(`{"host":"localhost","port":0,"mode":"other"}`), 0644)
+	config := &validateTest{readers: []Reader{&FileReader{Dir: dir, Name: "validate.json"}}}
+	if _, err := ReadConfigurator(config); !errors.As(err, &validationErr) {
+		t.Fatalf("expected validation error, got %v", err)
+	} else if len(validationErr.Fields) != 1 || validationErr.Fields[0].Location != filepath.Join(dir, "validate.json") {
+		t.Errorf("unexpected field errors: %v", validationErr)
+	}
+
+	// 校验失败时不回退到后续读取器
+	_ = os.WriteFile(filepath.Join(dir, "fallback.json"), []byte(`{"host":"localhost","port":80,"mode":"single"}`), 0644)
+	config = &validateTest{readers: []Reader{&FileReader{Dir: dir, Name: "validate.json"}, &FileReader{Dir: dir, Name: "fallback.json"}}}
+	if _, err := ReadConfigurator(config); !errors.As(err, &validationErr) {
+		t.Fatalf("expected validation error without fallback, got %v", err)
+	}
+
+	// 代码中构建的配置同样根据validate标签校验
+	config = &validateTest{Host: "localhost", Port: 70000}
+	if location, err := ReadConfigurator(config); !errors.As(err, &validationErr) || location != "self" {
+		t.Fatalf("expected validation error for self config, got %v", err)
+	} else if len(validationErr.Fields) != 1 || validationErr.Fields[0].Location != "self" {
+		t.Errorf("unexpected field errors: %v", validationErr)
+	}
+}
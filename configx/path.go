@@ -0,0 +1,186 @@
+package configx
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-xuan/utilx/errorx"
+)
+
+// 字段路径中的一步，用于按路径设置嵌套字段
+type pathStep struct {
+	field int           // 结构体字段下标，-1表示非结构体字段
+	index int           // 切片下标，-1表示非切片下标
+	key   reflect.Value // map的key，无效值表示非map的key
+	name  string        // 路径名称，用于记录字段来源
+}
+
+func fieldStep(i int, name string) pathStep {
+	return pathStep{field: i, index: -1, name: name}
+}
+
+func indexStep(i int) pathStep {
+	return pathStep{field: -1, index: i}
+}
+
+func keyStep(key string) pathStep {
+	return pathStep{field: -1, index: -1, key: reflect.ValueOf(key), name: key}
+}
+
+// 按路径设置值，路径中的空指针、nil map以及长度不足的切片将被自动创建或扩容，返回字段路径
+func setPath(val reflect.Value, steps []pathStep, value string) (string, error) {
+	var path string
+	err := applyPath(val, steps, value, &path)
+	return path, err
+}
+
+func applyPath(val reflect.Value, steps []pathStep, value string, path *string) error {
+	for val.Kind() == reflect.Pointer && len(steps) > 0 {
+		if val.IsNil() {
+			val.Set(reflect.New(val.Type().Elem()))
+		}
+		val = val.Elem()
+	}
+	if len(steps) == 0 {
+		return setValue(val, value)
+	}
+	step, rest := steps[0], steps[1:]
+	switch {
+	case step.field >= 0:
+		if step.name != "" { // 匿名嵌入字段不计入路径
+			*path = joinPath(*path, step.name)
+		}
+		return applyPath(val.Field(step.field), rest, value, path)
+	case step.index >= 0:
+		*path = fmt.Sprintf("%s[%d]", *path, step.index)
+		if val.Kind() == reflect.Array {
+			return applyPath(val.Index(step.index), rest, value, path)
+		}
+		if step.index >= val.Len() {
+			grown := reflect.MakeSlice(val.Type(), step.index+1, step.index+1)
+			reflect.Copy(grown, val)
+			val.Set(grown)
+		}
+		return applyPath(val.Index(step.index), rest, value, path)
+	default:
+		*path = joinPath(*path, step.name)
+		if val.IsNil() {
+			val.Set(reflect.MakeMap(val.Type()))
+		}
+		// map的值不可寻址，复制后设置再写回
+		key := step.key.Convert(val.Type().Key())
+		elem := reflect.New(val.Type().Elem()).Elem()
+		if existing := val.MapIndex(key); existing.IsValid() {
+			elem.Set(existing)
+		}
+		if err := applyPath(elem, rest, value, path); err != nil {
+			return err
+		}
+		val.SetMapIndex(key, elem)
+		return nil
+	}
+}
+
+// 将字符串转换为字段类型并赋值，切片以英文逗号分隔
+func setValue(val reflect.Value, value string) error {
+	if val.Kind() == reflect.Pointer {
+		if val.IsNil() {
+			val.Set(reflect.New(val.Type().Elem()))
+		}
+		return setValue(val.Elem(), value)
+	}
+	switch val.Kind() {
+	case reflect.String:
+		val.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return errorx.Wrap(err, "parse bool failed")
+		}
+		val.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if val.Type() == reflect.TypeOf(time.Duration(0)) {
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return errorx.Wrap(err, "parse duration failed")
+			}
+			val.SetInt(int64(d))
+			return nil
+		}
+		n, err := strconv.ParseInt(value, 10, val.Type().Bits())
+		if err != nil {
+			return errorx.Wrap(err, "parse int failed")
+		}
+		val.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, val.Type().Bits())
+		if err != nil {
+			return errorx.Wrap(err, "parse uint failed")
+		}
+		val.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, val.Type().Bits())
+		if err != nil {
+			return errorx.Wrap(err, "parse float failed")
+		}
+		val.SetFloat(f)
+	case reflect.Interface:
+		val.Set(reflect.ValueOf(value))
+	case reflect.Slice:
+		var items []string
+		if value != "" {
+			items = strings.Split(value, ",")
+		}
+		slice := reflect.MakeSlice(val.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := setValue(slice.Index(i), strings.TrimSpace(item)); err != nil {
+				return err
+			}
+		}
+		val.Set(slice)
+	default:
+		return errorx.Sprintf("unsupported kind: %s", val.Kind())
+	}
+	return nil
+}
+
+// 是否可以直接由字符串赋值的类型
+func isLeafType(typ reflect.Type) bool {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	switch typ.Kind() {
+	case reflect.String, reflect.Bool, reflect.Interface,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Slice:
+		elem := typ.Elem()
+		return elem.Kind() != reflect.Slice && elem.Kind() != reflect.Interface && isLeafType(elem)
+	default:
+		return false
+	}
+}
+
+// 字段的全部候选名称，包括json标签、yaml标签以及字段名
+func fieldNames(field reflect.StructField) []string {
+	names := []string{fieldName(field)}
+	if tag := field.Tag.Get("yaml"); tag != "" && tag != "-" {
+		if name, _, _ := strings.Cut(tag, ","); name != "" && name != names[0] {
+			names = append(names, name)
+		}
+	}
+	if field.Name != names[0] {
+		names = append(names, field.Name)
+	}
+	return names
+}
+
+// 统一名称格式，忽略大小写、下划线以及中划线
+func normalizeName(name string) string {
+	return strings.ToUpper(strings.NewReplacer("_", "", "-", "").Replace(name))
+}
@@ -2,68 +2,340 @@ package configx
 
 import (
 	"errors"
+	"io"
+	"reflect"
+	"sync"
+	"time"
 
-	"github.com/go-xuan/typex"
 	"github.com/go-xuan/utilx/errorx"
+	log "github.com/sirupsen/logrus"
 )
 
-// Pool 通用客户端池，提供多数据源客户端管理能力
-type Pool[C any] struct {
-	enum *typex.Enum[string, C]
+const (
+	DefaultSource      = "default"        // 默认数据源名称
+	DefaultRetireDelay = 30 * time.Second // 被替换或者移除的客户端默认延迟关闭时间
+)
+
+// PoolAction 客户端池变更类型
+type PoolAction string
+
+const (
+	PoolAdd     PoolAction = "add"     // 添加数据源
+	PoolReplace PoolAction = "replace" // 替换数据源客户端
+	PoolRemove  PoolAction = "remove"  // 移除数据源
+)
+
+// PoolListener 客户端池变更监听函数，client为变更后的客户端，移除时为被移除的客户端
+type PoolListener[C io.Closer] func(action PoolAction, source string, client C)
+
+// 客户端池中的客户端，记录正在使用的次数，被替换或者移除的客户端在使用结束后关闭
+type poolEntry[C io.Closer] struct {
+	client  C
+	refs    int         // 正在使用的次数
+	retired bool        // 是否已被替换或者移除
+	timer   *time.Timer // 延迟关闭的定时器
+}
+
+// Pool 通用客户端池，提供多数据源客户端管理能力，并发安全
+// 1. 数据源可在运行时添加、替换以及移除，例如按租户动态添加数据源
+// 2. 替换或者移除的客户端在通过 Acquire 获取的使用全部释放后，再延迟 SetRetireDelay 指定的时间关闭
+// 通过 Get、Find 获取客户端的调用方不计入使用次数，仅由延迟关闭时间保护，长时间使用时应通过 Acquire 获取
+// 3. default 为默认数据源的别名，默认数据源可通过 SetDefault 指定，未指定时为名称为default的数据源或者首个添加的数据源
+type Pool[C io.Closer] struct {
+	mu        sync.RWMutex
+	sources   []string                 // 数据源名称，按照添加顺序
+	entries   map[string]*poolEntry[C] // 数据源客户端
+	def       string                   // 指定的默认数据源
+	listeners []PoolListener[C]        // 变更监听函数
+	delay     time.Duration            // 延迟关闭时间
+	retiring  map[*poolEntry[C]]string // 等待延迟关闭的客户端
 }
 
 // NewPool 创建客户端池
-func NewPool[C any]() *Pool[C] {
-	return &Pool[C]{}
+func NewPool[C io.Closer]() *Pool[C] {
+	return &Pool[C]{delay: DefaultRetireDelay}
+}
+
+// SetRetireDelay 设置被替换或者移除的客户端的延迟关闭时间，小于等于0时立即关闭
+func (p *Pool[C]) SetRetireDelay(delay time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.delay = delay
 }
 
 // Initialized 是否已初始化
 func (p *Pool[C]) Initialized() bool {
-	return p.enum != nil && p.enum.Len() > 0
+	return p.Len() > 0
 }
 
-// Add 添加客户端，首个添加的客户端同时设为 default
+// SetDefault 指定默认数据源，数据源可在指定之后再添加
+func (p *Pool[C]) SetDefault(source string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.def = source
+}
+
+// Default 获取默认数据源名称，客户端池为空时返回空字符串
+func (p *Pool[C]) Default() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.defaultSource()
+}
+
+// Listen 添加变更监听函数，监听函数在变更完成后同步调用
+func (p *Pool[C]) Listen(listener PoolListener[C]) {
+	if listener == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.listeners = append(p.listeners, listener)
+}
+
+// Add 添加客户端，数据源已存在时等同于 Replace
 func (p *Pool[C]) Add(source string, client C) {
-	if p.enum == nil {
-		p.enum = typex.NewStringEnum[C]()
-		p.enum.Add("default", client)
+	p.Replace(source, client)
+}
+
+// Replace 替换数据源的客户端，数据源不存在时添加，旧客户端在正在进行的使用全部释放后关闭
+func (p *Pool[C]) Replace(source string, client C) {
+	p.mu.Lock()
+	if p.entries == nil {
+		p.entries = make(map[string]*poolEntry[C])
+	}
+	action := PoolAdd
+	old, exist := p.entries[source]
+	if exist {
+		action = PoolReplace
+	} else {
+		p.sources = append(p.sources, source)
 	}
-	p.enum.Add(source, client)
+	p.entries[source] = &poolEntry[C]{client: client}
+	var closeOld bool
+	if exist && !sameClient(old.client, client) {
+		old.retired = true
+		closeOld = old.refs == 0 && p.retire(source, old)
+	}
+	listeners := p.listeners
+	p.mu.Unlock()
+
+	if closeOld {
+		closeRetired(source, old.client)
+	}
+	notify(listeners, action, source, client)
 }
 
-// Get 获取客户端，未指定 source 时返回 default
-func (p *Pool[C]) Get(source ...string) C {
-	if len(source) > 0 && source[0] != "" {
-		if client := p.enum.Get(source[0]); any(client) != nil {
-			return client
+// Remove 移除数据源，客户端在正在进行的使用全部释放后关闭，数据源不存在时返回错误
+func (p *Pool[C]) Remove(source string) error {
+	p.mu.Lock()
+	source = p.resolve(source)
+	entry, ok := p.entries[source]
+	if !ok {
+		p.mu.Unlock()
+		return errorx.Sprintf("client source not found: %s", source)
+	}
+	delete(p.entries, source)
+	for i, name := range p.sources {
+		if name == source {
+			p.sources = append(p.sources[:i:i], p.sources[i+1:]...)
+			break
 		}
 	}
-	return p.enum.Get("default")
+	entry.retired = true
+	closeNow := entry.refs == 0 && p.retire(source, entry)
+	listeners := p.listeners
+	p.mu.Unlock()
+
+	if closeNow {
+		closeRetired(source, entry.client)
+	}
+	notify(listeners, PoolRemove, source, entry.client)
+	return nil
+}
+
+// Find 获取指定数据源的客户端，不存在时不会返回默认数据源
+func (p *Pool[C]) Find(source string) (C, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if entry, ok := p.entries[p.resolve(source)]; ok {
+		return entry.client, true
+	}
+	var zero C
+	return zero, false
 }
 
-// Range 遍历所有客户端
+// Get 获取客户端，未指定 source 时返回默认数据源，数据源不存在时返回错误
+func (p *Pool[C]) Get(source ...string) (C, error) {
+	name := DefaultSource
+	if len(source) > 0 && source[0] != "" {
+		name = source[0]
+	}
+	client, ok := p.Find(name)
+	if !ok {
+		return client, errorx.Sprintf("client source not found: %s", name)
+	}
+	return client, nil
+}
+
+// MustGet 获取客户端，数据源不存在时panic
+func (p *Pool[C]) MustGet(source ...string) C {
+	client, err := p.Get(source...)
+	if err != nil {
+		panic(err)
+	}
+	return client
+}
+
+// Acquire 获取客户端并标记为使用中，使用结束后必须调用release释放
+// 使用期间客户端被替换或者移除时，客户端将在释放后关闭
+func (p *Pool[C]) Acquire(source ...string) (client C, release func(), err error) {
+	name := DefaultSource
+	if len(source) > 0 && source[0] != "" {
+		name = source[0]
+	}
+	p.mu.Lock()
+	name = p.resolve(name)
+	entry, ok := p.entries[name]
+	if !ok {
+		p.mu.Unlock()
+		return client, func() {}, errorx.Sprintf("client source not found: %s", name)
+	}
+	entry.refs++
+	p.mu.Unlock()
+
+	var once sync.Once
+	release = func() {
+		once.Do(func() {
+			p.mu.Lock()
+			entry.refs--
+			closeNow := entry.retired && entry.refs == 0 && p.retire(name, entry)
+			p.mu.Unlock()
+			if closeNow {
+				closeRetired(name, entry.client)
+			}
+		})
+	}
+	return entry.client, release, nil
+}
+
+// Sources 按照添加顺序返回全部数据源名称，不包含 default 别名
+func (p *Pool[C]) Sources() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	sources := make([]string, len(p.sources))
+	copy(sources, p.sources)
+	return sources
+}
+
+// Range 按照添加顺序遍历所有客户端，不包含 default 别名，f 返回 false 时停止遍历
 func (p *Pool[C]) Range(f func(source string, client C) bool) {
-	if p.enum != nil {
-		p.enum.Range(f)
+	for _, source := range p.Sources() {
+		if client, ok := p.Find(source); ok && !f(source, client) {
+			return
+		}
 	}
 }
 
-// Len 返回客户端数量
+// Len 返回数据源数量
 func (p *Pool[C]) Len() int {
-	if p.enum == nil {
-		return 0
-	}
-	return p.enum.Len()
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return len(p.sources)
 }
 
-// Close 遍历关闭所有客户端
-func (p *Pool[C]) Close(closeFn func(C) error) error {
+// Close 按照添加顺序的逆序关闭所有客户端并清空客户端池，等待延迟关闭的客户端立即关闭，正在使用的客户端在释放后关闭
+func (p *Pool[C]) Close() error {
+	p.mu.Lock()
+	sources, entries, retiring := p.sources, p.entries, p.retiring
+	p.sources, p.entries, p.retiring = nil, nil, nil
+	p.mu.Unlock()
+
 	var errs []error
-	p.Range(func(source string, client C) bool {
-		if e := closeFn(client); e != nil {
-			errs = append(errs, errorx.Wrap(e, "close client failed"))
+	for entry, source := range retiring {
+		entry.timer.Stop()
+		if err := entry.client.Close(); err != nil {
+			errs = append(errs, errorx.Wrap(err, "close client failed: "+source))
+		}
+	}
+	for i := len(sources) - 1; i >= 0; i-- {
+		entry := entries[sources[i]]
+		p.mu.Lock()
+		entry.retired = true
+		closeNow := entry.refs == 0
+		p.mu.Unlock()
+		if closeNow {
+			if err := entry.client.Close(); err != nil {
+				errs = append(errs, errorx.Wrap(err, "close client failed: "+sources[i]))
+			}
 		}
+	}
+	return errors.Join(errs...)
+}
+
+// 将 default 别名解析为默认数据源
+func (p *Pool[C]) resolve(source string) string {
+	if source == DefaultSource {
+		if def := p.defaultSource(); def != "" {
+			return def
+		}
+	}
+	return source
+}
+
+// 默认数据源：指定的数据源 > 名称为default的数据源 > 首个添加的数据源
+func (p *Pool[C]) defaultSource() string {
+	if _, ok := p.entries[p.def]; ok && p.def != "" {
+		return p.def
+	} else if _, ok = p.entries[DefaultSource]; ok {
+		return DefaultSource
+	} else if len(p.sources) > 0 {
+		return p.sources[0]
+	}
+	return ""
+}
+
+// 已被替换或者移除且未在使用的客户端，延迟关闭时间内仍可被 Get 的调用方使用，需持有锁调用，返回是否需要立即关闭
+func (p *Pool[C]) retire(source string, entry *poolEntry[C]) bool {
+	if p.delay <= 0 {
 		return true
+	}
+	if p.retiring == nil {
+		p.retiring = make(map[*poolEntry[C]]string)
+	}
+	p.retiring[entry] = source
+	entry.timer = time.AfterFunc(p.delay, func() {
+		p.mu.Lock()
+		_, ok := p.retiring[entry]
+		delete(p.retiring, entry)
+		p.mu.Unlock()
+		if ok {
+			closeRetired(source, entry.client)
+		}
 	})
-	return errors.Join(errs...)
+	return false
+}
+
+// 关闭已被替换或者移除的客户端
+func closeRetired[C io.Closer](source string, client C) {
+	if err := client.Close(); err != nil {
+		log.WithField("source", source).WithError(err).Warn("close retired client failed")
+	}
+}
+
+// 调用变更监听函数
+func notify[C io.Closer](listeners []PoolListener[C], action PoolAction, source string, client C) {
+	for _, listener := range listeners {
+		listener(action, source, client)
+	}
+}
+
+// 是否为同一客户端，重复添加同一客户端时不会关闭
+func sameClient[C io.Closer](a, b C) bool {
+	if isNil(a) || isNil(b) {
+		return isNil(a) && isNil(b)
+	}
+	if !reflect.TypeOf(a).Comparable() || reflect.TypeOf(a) != reflect.TypeOf(b) {
+		return false
+	}
+	return any(a) == any(b)
 }
@@ -0,0 +1,162 @@
+package configx
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type poolTestClient struct {
+	name   string
+	closed atomic.Int32
+}
+
+func (c *poolTestClient) Close() error {
+	c.closed.Add(1)
+	return nil
+}
+
+func TestPoolReplace(t *testing.T) {
+	pool := NewPool[*poolTestClient]()
+	pool.SetRetireDelay(0)
+	var actions []string
+	pool.Listen(func(action PoolAction, source string, client *poolTestClient) {
+		actions = append(actions, fmt.Sprintf("%s:%s:%s", action, source, client.name))
+	})
+
+	old := &poolTestClient{name: "old"}
+	pool.Add("tenant", old)
+	client, release, err := pool.Acquire("tenant")
+	if err != nil || client != old {
+		t.Fatalf("unexpected acquire result: %v %v", client, err)
+	}
+
+	// 使用中的旧客户端在释放后关闭
+	updated := &poolTestClient{name: "new"}
+	pool.Replace("tenant", updated)
+	if got, _ := pool.Get("tenant"); got != updated {
+		t.Fatalf("expected replaced client, got %v", got.name)
+	}
+	if old.closed.Load() != 0 {
+		t.Fatal("old client should not be closed while in use")
+	}
+	release()
+	release()
+	if old.closed.Load() != 1 {
+		t.Fatalf("old client should be closed once after release, got %d", old.closed.Load())
+	}
+
+	// 重复添加同一客户端不会关闭
+	pool.Add("tenant", updated)
+	if updated.closed.Load() != 0 {
+		t.Fatal("re-adding the same client should not close it")
+	}
+
+	if err = pool.Remove("tenant"); err != nil {
+		t.Fatal(err)
+	}
+	if updated.closed.Load() != 1 {
+		t.Fatal("removed client should be closed")
+	}
+	if err = pool.Remove("tenant"); err == nil {
+		t.Fatal("expected error for removing unknown source")
+	}
+	expected := []string{"add:tenant:old", "replace:tenant:new", "replace:tenant:new", "remove:tenant:new"}
+	if fmt.Sprint(actions) != fmt.Sprint(expected) {
+		t.Fatalf("unexpected actions: %v", actions)
+	}
+}
+
+func TestPoolDefault(t *testing.T) {
+	pool := NewPool[*poolTestClient]()
+	if _, err := pool.Get(); err == nil {
+		t.Fatal("expected error for empty pool")
+	}
+	pool.SetDefault("db2")
+	pool.Add("db1", &poolTestClient{name: "db1"})
+	if client, _ := pool.Get(); client.name != "db1" {
+		t.Fatalf("default should fall back to the first source before db2 is added, got %s", client.name)
+	}
+	pool.Add("db2", &poolTestClient{name: "db2"})
+	if client, _ := pool.Get(DefaultSource); client.name != "db2" {
+		t.Fatalf("default should be db2, got %s", client.name)
+	}
+	if err := pool.Remove("db2"); err != nil {
+		t.Fatal(err)
+	}
+	if pool.Default() != "db1" {
+		t.Fatalf("default should fall back to db1, got %s", pool.Default())
+	}
+	if _, err := pool.Get("db2"); err == nil {
+		t.Fatal("expected error for removed source")
+	}
+}
+
+func TestPoolConcurrent(t *testing.T) {
+	pool := NewPool[*poolTestClient]()
+	pool.SetRetireDelay(time.Millisecond)
+	var clients sync.Map
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				source := fmt.Sprintf("tenant%d", j%4)
+				client := &poolTestClient{name: fmt.Sprintf("%d-%d", i, j)}
+				clients.Store(client, struct{}{})
+				pool.Replace(source, client)
+				if c, release, err := pool.Acquire(source); err == nil {
+					_ = c.name
+					release()
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	if pool.Len() != 4 {
+		t.Fatalf("expected 4 sources, got %d", pool.Len())
+	}
+	if err := pool.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// 全部客户端均已关闭且仅关闭一次
+	clients.Range(func(key, _ any) bool {
+		if closed := key.(*poolTestClient).closed.Load(); closed != 1 {
+			t.Errorf("client %s closed %d times", key.(*poolTestClient).name, closed)
+		}
+		return true
+	})
+}
+
+func TestPoolRetireDelay(t *testing.T) {
+	pool := NewPool[*poolTestClient]()
+	pool.SetRetireDelay(50 * time.Millisecond)
+
+	// 通过Get获取的客户端在延迟关闭时间内仍可使用
+	old := &poolTestClient{name: "old"}
+	pool.Add("tenant", old)
+	pool.Replace("tenant", &poolTestClient{name: "new"})
+	if old.closed.Load() != 0 {
+		t.Fatal("replaced client should not be closed before the retire delay")
+	}
+	time.Sleep(100 * time.Millisecond)
+	if old.closed.Load() != 1 {
+		t.Fatal("replaced client should be closed after the retire delay")
+	}
+
+	// 关闭客户端池时立即关闭等待延迟关闭的客户端
+	removed, _ := pool.Get("tenant")
+	if err := pool.Remove("tenant"); err != nil {
+		t.Fatal(err)
+	}
+	if err := pool.Close(); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if removed.closed.Load() != 1 {
+		t.Fatalf("removed client should be closed once, got %d", removed.closed.Load())
+	}
+}
@@ -0,0 +1,167 @@
+package configx
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-xuan/utilx/errorx"
+	"github.com/go-xuan/utilx/marshalx"
+)
+
+// ProfileEnv 指定环境的环境变量
+const ProfileEnv = "QUANX_PROFILE"
+
+// 当前环境，例如dev/test/prod，默认读取环境变量
+var profile = os.Getenv(ProfileEnv)
+
+// SetProfile 设置当前环境，设置后配置读取器会将 name-profile.ext 深度合并到 name.ext 之上
+func SetProfile(p string) {
+	profile = strings.TrimSpace(p)
+}
+
+// GetProfile 获取当前环境
+func GetProfile() string {
+	return profile
+}
+
+// ProfileName 获取环境配置文件名，例如 database.yaml -> database-prod.yaml
+func ProfileName(name, profile string) string {
+	if profile == "" {
+		return name
+	}
+	ext := filepath.Ext(name)
+	return strings.TrimSuffix(name, ext) + "-" + profile + ext
+}
+
+// Layer 配置层，多个配置层按顺序深度合并，后面的配置层覆盖前面的配置层
+type Layer struct {
+	Location string // 配置位置
+	Data     []byte // 配置内容
+}
+
+// MergeLayers 按顺序深度合并配置层，返回合并后的配置内容以及每个配置字段的来源
+// 合并规则：
+// 1. map（包括结构体）递归合并，同名字段以后面的配置层为准
+// 2. 切片以及标量整体替换，不按下标合并，例如 dbx.Configs 在环境配置中出现时将完整替换基础配置
+// 字段来源的key为字段路径，例如 "server.port.http"、"[0].host"
+func MergeLayers(format string, layers ...Layer) ([]byte, map[string]string, error) {
+	if len(layers) == 0 {
+		return nil, nil, errorx.New("config layers is empty")
+	}
+	marshal := marshalx.Apply(format)
+	var merged any
+	origins := make(map[string]string)
+	for i, layer := range layers {
+		var value any
+		if err := marshal.Unmarshal(layer.Data, &value); err != nil {
+			if len(layers) == 1 {
+				// 单个配置层无需合并，仅无法记录字段来源
+				return layer.Data, nil, nil
+			}
+			return nil, nil, errorx.Wrap(err, "unmarshal config layer failed: "+layer.Location)
+		}
+		value = normalize(value)
+		if i == 0 {
+			merged = value
+			recordOrigins(origins, "", value, layer.Location)
+		} else {
+			merged = mergeValue(merged, value, "", layer.Location, origins)
+		}
+	}
+	if len(layers) == 1 {
+		return layers[0].Data, origins, nil
+	}
+	data, err := marshal.Marshal(merged)
+	if err != nil {
+		return nil, nil, errorx.Wrap(err, "marshal merged config failed")
+	}
+	return data, origins, nil
+}
+
+// FormatOrigins 格式化字段来源，按字段路径排序，用于调试输出
+func FormatOrigins(origins map[string]string) string {
+	paths := make([]string, 0, len(origins))
+	for path := range origins {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	var sb strings.Builder
+	for _, path := range paths {
+		sb.WriteString(fmt.Sprintf("%s <- %s\n", path, origins[path]))
+	}
+	return sb.String()
+}
+
+// 深度合并，src覆盖dst
+func mergeValue(dst, src any, path, location string, origins map[string]string) any {
+	dstMap, ok1 := dst.(map[string]any)
+	srcMap, ok2 := src.(map[string]any)
+	if !ok1 || !ok2 {
+		clearOrigins(origins, path)
+		recordOrigins(origins, path, src, location)
+		return src
+	}
+	for key, value := range srcMap {
+		dstMap[key] = mergeValue(dstMap[key], value, joinPath(path, key), location, origins)
+	}
+	return dstMap
+}
+
+// 记录字段来源，仅记录叶子节点
+func recordOrigins(origins map[string]string, path string, value any, location string) {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			recordOrigins(origins, joinPath(path, key), item, location)
+		}
+	case []any:
+		for i, item := range v {
+			recordOrigins(origins, fmt.Sprintf("%s[%d]", path, i), item, location)
+		}
+	default:
+		origins[path] = location
+	}
+}
+
+// 清除指定路径及其子路径的字段来源
+func clearOrigins(origins map[string]string, path string) {
+	for key := range origins {
+		if path == "" || key == path || strings.HasPrefix(key, path+".") || strings.HasPrefix(key, path+"[") {
+			delete(origins, key)
+		}
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// 统一map类型，部分yaml解析器会将map解析为map[any]any
+func normalize(value any) any {
+	switch v := value.(type) {
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for key, item := range v {
+			m[fmt.Sprint(key)] = normalize(item)
+		}
+		return m
+	case map[string]any:
+		for key, item := range v {
+			v[key] = normalize(item)
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = normalize(item)
+		}
+		return v
+	default:
+		return value
+	}
+}
@@ -0,0 +1,65 @@
+package configx
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMergeLayers(t *testing.T) {
+	base := []byte(`{"server":{"name":"app","port":{"http":8080,"grpc":8081}},"hosts":["a","b"]}`)
+	prod := []byte(`{"server":{"port":{"http":80}},"hosts":["c"]}`)
+	data, origins, err := MergeLayers("json", Layer{Location: "base", Data: base}, Layer{Location: "prod", Data: prod})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var merged struct {
+		Server struct {
+			Name string         `json:"name"`
+			Port map[string]int `json:"port"`
+		} `json:"server"`
+		Hosts []string `json:"hosts"`
+	}
+	if err = json.Unmarshal(data, &merged); err != nil {
+		t.Fatal(err)
+	}
+	if merged.Server.Name != "app" || merged.Server.Port["http"] != 80 || merged.Server.Port["grpc"] != 8081 {
+		t.Errorf("unexpected merged server: %+v", merged.Server)
+	}
+	if len(merged.Hosts) != 1 || merged.Hosts[0] != "c" {
+		t.Errorf("slice should be replaced, got %v", merged.Hosts)
+	}
+	expects := map[string]string{
+		"server.name":      "base",
+		"server.port.http": "prod",
+		"server.port.grpc": "base",
+		"hosts[0]":         "prod",
+	}
+	for path, location := range expects {
+		if origins[path] != location {
+			t.Errorf("origin of %s: expected %s, got %s", path, location, origins[path])
+		}
+	}
+	if _, ok := origins["hosts[1]"]; ok {
+		t.Error("origin of replaced slice element should be removed")
+	}
+}
+
+func TestFileReaderProfile(t *testing.T) {
+	dir := t.TempDir()
+	_ = os.WriteFile(filepath.Join(dir, "demo.json"), []byte(`[{"source":"default","host":"localhost"}]`), 0644)
+	_ = os.WriteFile(filepath.Join(dir, "demo-prod.json"), []byte(`[{"source":"default","host":"prod-db"}]`), 0644)
+
+	var configs []map[string]string
+	reader := &FileReader{Dir: dir, Name: "demo.json", Profile: "prod"}
+	if err := reader.Read(&configs); err != nil {
+		t.Fatal(err)
+	}
+	if len(configs) != 1 || configs[0]["host"] != "prod-db" {
+		t.Errorf("unexpected configs: %v", configs)
+	}
+	if origin := reader.Origins()["[0].host"]; origin != filepath.Join(dir, "demo-prod.json") {
+		t.Errorf("unexpected origin: %s", origin)
+	}
+}
@@ -1,13 +1,16 @@
 package configx
 
 import (
+	"time"
+
 	"github.com/go-xuan/utilx/errorx"
 	"github.com/go-xuan/utilx/filex"
 )
 
 var (
-	defaultFileAnchor = "conf"    // 默认文件读取器锚点
-	defaultTagAnchor  = "default" // 默认tag读取器锚点
+	defaultFileAnchor = "conf"          // 默认文件读取器锚点
+	defaultTagAnchor  = "default"       // 默认tag读取器锚点
+	watchInterval     = 3 * time.Second // 本地文件监听轮询间隔
 )
 
 // SetFileReaderAnchor 设置本地文件读取器锚点
@@ -24,7 +27,14 @@ func SetTagReaderAnchor(anchor string) {
 	}
 }
 
-// ReaderRead 使用指定的配置读取器读取配置
+// SetWatchInterval 设置本地文件监听轮询间隔
+func SetWatchInterval(interval time.Duration) {
+	if interval > 0 {
+		watchInterval = interval
+	}
+}
+
+// ReaderRead 使用指定的配置读取器读取配置，并解析配置中的密钥占位符以及加密值
 func ReaderRead(reader Reader, v any) error {
 	if reader == nil {
 		return errorx.New("reader is nil")
@@ -32,6 +42,9 @@ func ReaderRead(reader Reader, v any) error {
 	if err := reader.Read(v); err != nil {
 		return errorx.Wrap(err, "read reader failed")
 	}
+	if err := ResolveSecrets(v); err != nil {
+		return errorx.Wrap(err, "read reader failed")
+	}
 	return nil
 }
 
@@ -0,0 +1,142 @@
+package configx
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-xuan/utilx/errorx"
+)
+
+// NewDirReader 创建目录读取器，dir为挂载目录，为相对路径时拼接锚点
+func NewDirReader(dir string) *DirReader {
+	return &DirReader{
+		Dir: dir,
+	}
+}
+
+// DirReader 目录读取器，用于读取Kubernetes挂载的ConfigMap/Secret，目录中每个文件对应一个配置项
+// 文件名为字段路径，文件内容为字段值（去除首尾空白），例如：
+// host -> host
+// server.port.http -> server.port.http
+// database.0.host -> database[0].host
+// 字段名称使用json/yaml标签并忽略大小写、下划线以及中划线，隐藏文件（包括Kubernetes的..data目录）以及与配置字段不匹配的文件将被忽略
+type DirReader struct {
+	Base    string            `json:"base"`   // 基础目录（锚点），Dir为相对路径时拼接
+	Dir     string            `json:"dir"`    // 挂载目录
+	Secret  bool              `json:"secret"` // 是否为Secret挂载目录，读取的值将在日志以及配置输出中脱敏
+	origins map[string]string // 字段来源
+}
+
+func (r *DirReader) Anchor(base string) {
+	if r.Base == "" {
+		r.Base = base
+	}
+}
+
+func (r *DirReader) Location() string {
+	return "dir@" + r.GetDir()
+}
+
+// GetDir 获取挂载目录
+func (r *DirReader) GetDir() string {
+	if r.Base == "" || filepath.IsAbs(r.Dir) {
+		return r.Dir
+	}
+	return filepath.Join(r.Base, r.Dir)
+}
+
+func (r *DirReader) Read(v any) error {
+	val := reflect.ValueOf(v)
+	if val.Kind() != reflect.Pointer || val.IsNil() {
+		return errorx.New("the config must be a non-nil pointer")
+	}
+	files, err := r.files()
+	if err != nil {
+		return errorx.Wrap(err, "read dir reader failed")
+	}
+	origins := make(map[string]string)
+	for _, file := range files {
+		name := filepath.Base(file)
+		steps, _, ok := matchFlag(val.Type(), strings.Split(name, "."))
+		if !ok {
+			continue
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return errorx.Wrap(err, "read file failed: "+file)
+		}
+		value := strings.TrimSpace(string(data))
+		path, err := setPath(val, steps, value)
+		if err != nil {
+			return errorx.Wrap(err, "read dir reader failed: "+file)
+		}
+		if r.Secret {
+			addSecret(value)
+		}
+		origins[path] = file
+	}
+	if len(origins) == 0 {
+		return errorx.Sprintf("no file matched the config field: %s", r.GetDir())
+	}
+	r.origins = origins
+	return nil
+}
+
+// Origins 获取各字段的来源文件，key为字段路径
+func (r *DirReader) Origins() map[string]string {
+	return r.origins
+}
+
+// Watch 轮询监听目录中文件的修改时间以及大小，Kubernetes更新挂载内容时回调onChange
+func (r *DirReader) Watch(onChange func()) (func(), error) {
+	stamp := func() string {
+		files, _ := r.files()
+		return fileStamp(files)
+	}
+	last := stamp()
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		ticker := time.NewTicker(watchInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if current := stamp(); current != last {
+					last = current
+					onChange()
+				}
+			}
+		}
+	}()
+	return cancel, nil
+}
+
+// 目录中的配置文件，按文件名排序
+func (r *DirReader) files() ([]string, error) {
+	dir := r.GetDir()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		file := filepath.Join(dir, entry.Name())
+		// Kubernetes挂载的文件为指向..data目录的软链接，需要判断链接目标
+		if info, err := os.Stat(file); err != nil || info.IsDir() {
+			continue
+		}
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	return files, nil
+}
@@ -0,0 +1,55 @@
+package configx
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type dirTestConfig struct {
+	Host     string `json:"host"`
+	Password string `json:"password"`
+	Server   struct {
+		Port map[string]int `json:"port"`
+	} `json:"server"`
+	Database []struct {
+		MaxOpenConns int `json:"maxOpenConns"`
+	} `json:"database"`
+}
+
+func TestDirReader(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"host":                      "localhost\n",
+		"password":                  "dir-secret\n",
+		"server.port.http":          "8080",
+		"database.0.max_open_conns": "20",
+		"unknown":                   "ignored",
+		"..data":                    "ignored",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reader := NewDirReader(filepath.Base(dir))
+	reader.Anchor(filepath.Dir(dir))
+	reader.Secret = true
+	config := &dirTestConfig{}
+	if err := reader.Read(config); err != nil {
+		t.Fatal(err)
+	}
+	if config.Host != "localhost" || config.Server.Port["http"] != 8080 || len(config.Database) != 1 || config.Database[0].MaxOpenConns != 20 {
+		t.Fatalf("unexpected config: %+v", config)
+	}
+	if reader.Origins()["server.port.http"] != filepath.Join(dir, "server.port.http") {
+		t.Fatalf("unexpected origins: %v", reader.Origins())
+	}
+	if MaskSecret("dir-secret") != MaskedSecret {
+		t.Fatal("secret value is not masked")
+	}
+	if err := NewDirReader(t.TempDir()).Read(&dirTestConfig{}); err == nil {
+		t.Fatal("expected error for empty dir")
+	}
+}
@@ -1,29 +1,189 @@
 package configx
 
 import (
+	"errors"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
 	"github.com/go-xuan/utilx/errorx"
 	"github.com/go-xuan/utilx/osx"
 )
 
-// NewEnvReader 创建环境变量读取器
-func NewEnvReader() Reader {
-	return &EnvReader{}
+// EnvPrefix 内置配置器的环境变量前缀，例如 QUANX_DATABASE_0_HOST
+const EnvPrefix = "QUANX"
+
+// ErrEnvNotMatched 没有匹配前缀的环境变量
+var ErrEnvNotMatched = errors.New("no env matched the prefix")
+
+// NewEnvReader 创建环境变量读取器，prefix为环境变量前缀，例如 QUANX_DATABASE
+func NewEnvReader(prefix ...string) *EnvReader {
+	reader := &EnvReader{}
+	if len(prefix) > 0 {
+		reader.Prefix = prefix[0]
+	}
+	return reader
 }
 
-// EnvReader 环境变量读取器，根据env标签读取环境变量作为配置值
-type EnvReader struct{}
+// EnvReader 环境变量读取器
+// 1. 根据env标签读取环境变量作为配置值
+// 2. 设置前缀后，将 前缀_字段路径 格式的环境变量映射至嵌套的结构体、切片以及map，例如前缀为QUANX时：
+// QUANX_DATABASE_0_HOST -> database[0].host
+// QUANX_SERVER_PORT_HTTP -> server.port.http
+// QUANX_CACHE__PREFIX -> cache.prefix
+// 字段名称使用json/yaml标签，忽略大小写以及下划线（max_open_conns、maxOpenConns均对应MAX_OPEN_CONNS），
+// 单下划线存在歧义时优先匹配最长的字段名称，双下划线表示层级分隔；切片使用数字下标，map的key转换为小写
+type EnvReader struct {
+	Prefix  string            // 环境变量前缀，为空时仅读取env标签，不为空时没有匹配的环境变量视为读取失败
+	origins map[string]string // 字段来源
+}
 
-func (r *EnvReader) Anchor(string) {
-	// 环境变量读取器不需要定义锚点
+func (r *EnvReader) Anchor(prefix string) {
+	if r.Prefix == "" {
+		r.Prefix = prefix
+	}
 }
 
 func (r *EnvReader) Read(config any) error {
 	if err := osx.SetValueFromEnv(config); err != nil {
 		return errorx.Wrap(err, "read env reader failed")
 	}
+	if r.Prefix == "" {
+		return nil
+	}
+	val := reflect.ValueOf(config)
+	if val.Kind() != reflect.Pointer || val.IsNil() {
+		return errorx.New("the config must be a non-nil pointer")
+	}
+	prefix := strings.TrimSuffix(strings.ToUpper(r.Prefix), "_") + "_"
+	environ := os.Environ()
+	sort.Strings(environ) // 保证切片扩容以及字段来源的顺序稳定
+	origins := make(map[string]string)
+	for _, env := range environ {
+		name, value, _ := strings.Cut(env, "=")
+		if len(name) <= len(prefix) || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		steps, ok := matchEnv(val.Type(), splitEnvName(name[len(prefix):]), 0)
+		if !ok {
+			continue
+		}
+		path, err := setPath(val, steps, value)
+		if err != nil {
+			return errorx.Wrap(err, "read env failed: "+name)
+		}
+		origins[path] = "env:" + name
+	}
+	if len(origins) == 0 {
+		return ErrEnvNotMatched
+	}
+	r.origins = origins
 	return nil
 }
 
 func (r *EnvReader) Location() string {
+	if r.Prefix != "" {
+		return "env@" + r.Prefix
+	}
 	return "env"
 }
+
+// Origins 获取各字段的来源，key为字段路径
+func (r *EnvReader) Origins() map[string]string {
+	return r.origins
+}
+
+// 环境变量名称分词
+type envToken struct {
+	text string // 分词内容
+	hard bool   // 与前一个分词之间是否为双下划线，双下划线不可合并
+}
+
+func splitEnvName(name string) []envToken {
+	var tokens []envToken
+	for i, group := range strings.Split(name, "__") {
+		for j, text := range strings.Split(group, "_") {
+			if text != "" {
+				tokens = append(tokens, envToken{text: text, hard: i > 0 && j == 0})
+			}
+		}
+	}
+	return tokens
+}
+
+// 从第i个分词开始，可以合并的分词结束位置（不包含）
+func envGroupEnd(tokens []envToken, i int) int {
+	j := i + 1
+	for j < len(tokens) && !tokens[j].hard {
+		j++
+	}
+	return j
+}
+
+func joinEnvTokens(tokens []envToken, sep string) string {
+	texts := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		texts = append(texts, token.text)
+	}
+	return strings.Join(texts, sep)
+}
+
+// 根据类型匹配环境变量分词，返回字段路径
+func matchEnv(typ reflect.Type, tokens []envToken, i int) ([]pathStep, bool) {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if i == len(tokens) {
+		return nil, isLeafType(typ)
+	}
+	switch typ.Kind() {
+	case reflect.Struct:
+		// 优先匹配最长的字段名称
+		for j := envGroupEnd(tokens, i); j > i; j-- {
+			name := strings.ToUpper(joinEnvTokens(tokens[i:j], ""))
+			for f := 0; f < typ.NumField(); f++ {
+				field := typ.Field(f)
+				if !field.IsExported() || field.Anonymous {
+					continue
+				}
+				for _, candidate := range fieldNames(field) {
+					if normalizeName(candidate) != name {
+						continue
+					}
+					if steps, ok := matchEnv(field.Type, tokens, j); ok {
+						return append([]pathStep{fieldStep(f, fieldName(field))}, steps...), true
+					}
+				}
+			}
+		}
+		for f := 0; f < typ.NumField(); f++ {
+			if field := typ.Field(f); field.Anonymous && field.IsExported() {
+				if steps, ok := matchEnv(field.Type, tokens, i); ok {
+					return append([]pathStep{fieldStep(f, "")}, steps...), true
+				}
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		index, err := strconv.Atoi(tokens[i].text)
+		if err != nil || index < 0 || (typ.Kind() == reflect.Array && index >= typ.Len()) {
+			return nil, false
+		}
+		if steps, ok := matchEnv(typ.Elem(), tokens, i+1); ok {
+			return append([]pathStep{indexStep(index)}, steps...), true
+		}
+	case reflect.Map:
+		if typ.Key().Kind() != reflect.String {
+			return nil, false
+		}
+		// 优先匹配最长的key
+		for j := envGroupEnd(tokens, i); j > i; j-- {
+			key := strings.ToLower(joinEnvTokens(tokens[i:j], "_"))
+			if steps, ok := matchEnv(typ.Elem(), tokens, j); ok {
+				return append([]pathStep{keyStep(key)}, steps...), true
+			}
+		}
+	}
+	return nil, false
+}
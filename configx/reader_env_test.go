@@ -0,0 +1,75 @@
+package configx
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type envTestServer struct {
+	Name string `json:"name"`
+	Port struct {
+		Http int `json:"http"`
+	} `json:"port"`
+}
+
+type envTestDatabase struct {
+	Host         string        `json:"host"`
+	Port         int           `json:"port"`
+	MaxOpenConns int           `json:"maxOpenConns" yaml:"max_open_conns"`
+	Timeout      time.Duration `json:"timeout"`
+	Enable       bool          `json:"enable"`
+}
+
+type envTestConfig struct {
+	Server   *envTestServer      `json:"server"`
+	Database *[]*envTestDatabase `json:"database"`
+	Cache    struct {
+		Prefix string `json:"prefix"`
+	} `json:"cache"`
+	Hosts  []string          `json:"hosts"`
+	Labels map[string]string `json:"labels"`
+}
+
+func TestEnvReader(t *testing.T) {
+	t.Setenv("QUANXTEST_SERVER_NAME", "demo")
+	t.Setenv("QUANXTEST_SERVER_PORT_HTTP", "8080")
+	t.Setenv("QUANXTEST_DATABASE_1_HOST", "db1")
+	t.Setenv("QUANXTEST_DATABASE_1_MAX_OPEN_CONNS", "20")
+	t.Setenv("QUANXTEST_DATABASE_1_TIMEOUT", "3s")
+	t.Setenv("QUANXTEST_DATABASE_0_ENABLE", "true")
+	t.Setenv("QUANXTEST_CACHE__PREFIX", "app:")
+	t.Setenv("QUANXTEST_HOSTS", "a,b")
+	t.Setenv("QUANXTEST_LABELS_TEAM_NAME", "core")
+	t.Setenv("QUANXTEST_UNKNOWN_FIELD", "ignored")
+
+	config := &envTestConfig{}
+	reader := NewEnvReader("QUANXTEST")
+	if err := reader.Read(config); err != nil {
+		t.Fatal(err)
+	}
+	if config.Server == nil || config.Server.Name != "demo" || config.Server.Port.Http != 8080 {
+		t.Fatalf("unexpected server: %+v", config.Server)
+	}
+	if config.Database == nil || len(*config.Database) != 2 {
+		t.Fatalf("unexpected database: %+v", config.Database)
+	}
+	db0, db1 := (*config.Database)[0], (*config.Database)[1]
+	if !db0.Enable || db1.Host != "db1" || db1.MaxOpenConns != 20 || db1.Timeout != 3*time.Second {
+		t.Fatalf("unexpected database: %+v %+v", db0, db1)
+	}
+	if config.Cache.Prefix != "app:" || len(config.Hosts) != 2 || config.Labels["team_name"] != "core" {
+		t.Fatalf("unexpected config: %+v", config)
+	}
+	if origin := reader.Origins()["database[1].host"]; origin != "env:QUANXTEST_DATABASE_1_HOST" {
+		t.Fatalf("unexpected origin: %s", origin)
+	}
+
+	t.Setenv("QUANXTEST_SERVER_PORT_HTTP", "abc")
+	if err := NewEnvReader("QUANXTEST").Read(&envTestConfig{}); err == nil {
+		t.Fatal("expected parse error")
+	}
+	if err := NewEnvReader("QUANXNONE").Read(&envTestConfig{}); !errors.Is(err, ErrEnvNotMatched) {
+		t.Fatalf("expected ErrEnvNotMatched, got %v", err)
+	}
+}
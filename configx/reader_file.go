@@ -2,7 +2,11 @@ package configx
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/go-xuan/utilx/errorx"
 	"github.com/go-xuan/utilx/filex"
@@ -17,10 +21,14 @@ func NewFileReader(name string) *FileReader {
 }
 
 // FileReader 本地文件读取器
+// 当前环境不为空时，name-profile.ext 将深度合并到 name.ext 之上，合并规则参考 MergeLayers
 type FileReader struct {
-	Dir  string `json:"dir"`  // 文件路径
-	Name string `json:"name"` // 文件名称
-	Data []byte `json:"data"` // 文件内容
+	Dir     string            `json:"dir"`     // 文件路径
+	Name    string            `json:"name"`    // 文件名称
+	Profile string            `json:"profile"` // 环境，为空时使用当前环境
+	Data    []byte            `json:"data"`    // 文件内容（合并后）
+	mu      sync.Mutex        // 缓存锁，Watch 在轮询协程中清除缓存
+	origins map[string]string // 字段来源
 }
 
 func (r *FileReader) Anchor(dir string) {
@@ -30,26 +38,75 @@ func (r *FileReader) Anchor(dir string) {
 }
 
 func (r *FileReader) Location() string {
+	if profile := r.GetProfile(); profile != "" {
+		return fmt.Sprintf("file@%s@%s", r.GetPath(), profile)
+	}
 	return fmt.Sprintf("file@%s", r.GetPath())
 }
 
 // Read 读取配置文件
 func (r *FileReader) Read(v any) error {
-	if r.Data == nil {
-		path := r.GetPath()
+	data, err := r.load()
+	if err != nil {
+		return err
+	}
+	if err = marshalx.Apply(r.Name).Unmarshal(data, v); err != nil {
+		return errorx.Wrap(err, "unmarshal file reader failed")
+	}
+	return nil
+}
+
+// 读取并合并配置文件，已读取过的配置直接使用缓存
+func (r *FileReader) load() ([]byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Data != nil {
+		return r.Data, nil
+	}
+	var layers []Layer
+	for _, path := range r.layerPaths() {
 		if !filex.Exists(path) {
-			return errorx.Sprintf("file not exist: %s", filex.Pwd(path))
+			continue
 		}
 		data, err := filex.ReadFile(path)
 		if err != nil {
-			return errorx.Wrap(err, "read file reader failed")
+			return nil, errorx.Wrap(err, "read file reader failed")
 		}
-		r.Data = data
+		layers = append(layers, Layer{Location: path, Data: data})
 	}
-	if err := marshalx.Apply(r.Name).Unmarshal(r.Data, v); err != nil {
-		return errorx.Wrap(err, "unmarshal file reader failed")
+	if len(layers) == 0 {
+		return nil, errorx.Sprintf("file not exist: %s", filex.Pwd(r.GetPath()))
 	}
-	return nil
+	data, origins, err := MergeLayers(r.Name, layers...)
+	if err != nil {
+		return nil, errorx.Wrap(err, "merge file layers failed")
+	}
+	r.Data, r.origins = data, origins
+	return data, nil
+}
+
+// Origins 获取各字段的来源文件，key为字段路径
+func (r *FileReader) Origins() map[string]string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.origins
+}
+
+// GetProfile 获取环境
+func (r *FileReader) GetProfile() string {
+	if r.Profile != "" {
+		return r.Profile
+	}
+	return GetProfile()
+}
+
+// 配置文件路径，按照合并顺序排列
+func (r *FileReader) layerPaths() []string {
+	paths := []string{r.GetPath()}
+	if profile := r.GetProfile(); profile != "" {
+		paths = append(paths, filepath.Join(r.Dir, ProfileName(r.Name, profile)))
+	}
+	return paths
 }
 
 // Write 写入配置文件
@@ -65,3 +122,43 @@ func (r *FileReader) GetPath() string {
 	r.Anchor(defaultFileAnchor)
 	return filepath.Join(r.Dir, r.Name)
 }
+
+// Watch 轮询监听配置文件（包括环境配置文件）的修改时间以及大小，文件变更时清除缓存并回调onChange
+func (r *FileReader) Watch(onChange func()) (func(), error) {
+	paths := r.layerPaths()
+	last := fileStamp(paths)
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(watchInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if current := fileStamp(paths); current != last {
+					last = current
+					r.mu.Lock()
+					r.Data = nil
+					r.mu.Unlock()
+					onChange()
+				}
+			}
+		}
+	}()
+	var once sync.Once
+	return func() { once.Do(func() { close(done) }) }, nil
+}
+
+// 文件状态（修改时间以及大小），用于判断文件是否变更
+func fileStamp(paths []string) string {
+	var sb strings.Builder
+	for _, path := range paths {
+		if info, err := os.Stat(path); err == nil {
+			sb.WriteString(fmt.Sprintf("%s:%d:%d;", path, info.ModTime().UnixNano(), info.Size()))
+		} else {
+			sb.WriteString(path + ":-;")
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,260 @@
+package configx
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/go-xuan/utilx/errorx"
+)
+
+var (
+	ErrFlagHelp       = errors.New("flag: help requested")             // 命令行参数包含 -h/--help
+	ErrFlagNotMatched = errors.New("no flag matched the config field") // 没有匹配配置字段的命令行参数
+)
+
+// NewFlagReader 创建命令行参数读取器，args为空时读取 os.Args[1:]
+func NewFlagReader(args ...string) *FlagReader {
+	return &FlagReader{Args: args}
+}
+
+// FlagReader 命令行参数读取器，根据字段路径将命令行参数映射至配置字段，例如：
+// --server.port.http=9000 -> server.port.http
+// --log.level debug -> log.level
+// --database.0.host=127.0.0.1 或者 --database[0].host=127.0.0.1 -> database[0].host
+// 字段名称使用json/yaml标签并忽略大小写、下划线以及中划线，切片使用数字下标，与配置字段不匹配的参数将被忽略
+// 参数包含 -h/--help 时输出参数说明（根据comment、default以及validate标签生成）并返回 ErrFlagHelp
+type FlagReader struct {
+	Args    []string          // 命令行参数，为空时读取 os.Args[1:]
+	Prefix  string            // 参数前缀，例如前缀为db时仅读取 --db.xxx 格式的参数
+	Output  io.Writer         // 参数说明输出，默认为标准错误输出
+	origins map[string]string // 字段来源
+}
+
+func (r *FlagReader) Anchor(prefix string) {
+	if r.Prefix == "" {
+		r.Prefix = prefix
+	}
+}
+
+func (r *FlagReader) Read(v any) error {
+	val := reflect.ValueOf(v)
+	if val.Kind() != reflect.Pointer || val.IsNil() {
+		return errorx.New("the config must be a non-nil pointer")
+	}
+	args := r.Args
+	if len(args) == 0 && len(os.Args) > 1 {
+		args = os.Args[1:]
+	}
+	origins := make(map[string]string)
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			break
+		} else if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		key := strings.TrimLeft(arg, "-")
+		if key == "h" || key == "help" {
+			output := r.Output
+			if output == nil {
+				output = os.Stderr
+			}
+			_, _ = fmt.Fprint(output, r.Usage(v))
+			return ErrFlagHelp
+		}
+		name, value, hasValue := strings.Cut(key, "=")
+		steps, leaf, ok := r.match(val.Type(), name)
+		if !ok {
+			continue
+		}
+		if !hasValue {
+			next := i+1 < len(args) && !strings.HasPrefix(args[i+1], "-")
+			if isBoolType(leaf) {
+				// 布尔参数仅在下一个参数为布尔值时取下一个参数，否则视为true
+				value = "true"
+				if next {
+					if _, err := strconv.ParseBool(args[i+1]); err == nil {
+						value = args[i+1]
+						i++
+					}
+				}
+			} else if next {
+				value = args[i+1]
+				i++
+			} else {
+				return errorx.Sprintf("flag needs a value: --%s", name)
+			}
+		}
+		path, err := setPath(val, steps, value)
+		if err != nil {
+			return errorx.Wrap(err, "read flag failed: --"+name)
+		}
+		origins[path] = "flag:--" + name
+	}
+	if len(origins) == 0 {
+		return ErrFlagNotMatched
+	}
+	r.origins = origins
+	return nil
+}
+
+func (r *FlagReader) Location() string {
+	if r.Prefix != "" {
+		return "flag@" + r.Prefix
+	}
+	return "flag"
+}
+
+// Origins 获取各字段的来源，key为字段路径
+func (r *FlagReader) Origins() map[string]string {
+	return r.origins
+}
+
+// Usage 根据配置结构体生成参数说明，说明内容取自comment标签，并附带默认值以及校验规则
+func (r *FlagReader) Usage(v any) string {
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 0, 4, 2, ' ', 0)
+	prefix := ""
+	if r.Prefix != "" {
+		prefix = r.Prefix + "."
+	}
+	writeUsage(w, reflect.TypeOf(v), prefix, "", 0)
+	_ = w.Flush()
+	return sb.String()
+}
+
+// Lookup 查找参数名称对应的配置字段，返回是否匹配以及是否为布尔字段，
+// 用于在其他命令行解析中判断 --key value 格式的下一个参数是否为该参数的值
+func (r *FlagReader) Lookup(v any, name string) (matched, isBool bool) {
+	_, leaf, ok := r.match(reflect.TypeOf(v), name)
+	return ok, ok && isBoolType(leaf)
+}
+
+// 匹配参数名称，返回字段路径以及字段类型
+func (r *FlagReader) match(typ reflect.Type, name string) ([]pathStep, reflect.Type, bool) {
+	if r.Prefix != "" {
+		if !strings.HasPrefix(name, r.Prefix+".") {
+			return nil, nil, false
+		}
+		name = name[len(r.Prefix)+1:]
+	}
+	name = strings.NewReplacer("[", ".", "]", "").Replace(name)
+	var segments []string
+	for _, segment := range strings.Split(name, ".") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	if len(segments) == 0 {
+		return nil, nil, false
+	}
+	return matchFlag(typ, segments)
+}
+
+// 根据类型逐级匹配参数名称
+func matchFlag(typ reflect.Type, segments []string) ([]pathStep, reflect.Type, bool) {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if len(segments) == 0 {
+		return nil, typ, isLeafType(typ)
+	}
+	segment, rest := segments[0], segments[1:]
+	prepend := func(step pathStep, steps []pathStep, leaf reflect.Type, ok bool) ([]pathStep, reflect.Type, bool) {
+		if !ok {
+			return nil, nil, false
+		}
+		return append([]pathStep{step}, steps...), leaf, true
+	}
+	switch typ.Kind() {
+	case reflect.Struct:
+		name := normalizeName(segment)
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			if !field.IsExported() || field.Anonymous {
+				continue
+			}
+			for _, candidate := range fieldNames(field) {
+				if normalizeName(candidate) == name {
+					steps, leaf, ok := matchFlag(field.Type, rest)
+					return prepend(fieldStep(i, fieldName(field)), steps, leaf, ok)
+				}
+			}
+		}
+		for i := 0; i < typ.NumField(); i++ {
+			if field := typ.Field(i); field.Anonymous && field.IsExported() {
+				if steps, leaf, ok := matchFlag(field.Type, segments); ok {
+					return prepend(fieldStep(i, ""), steps, leaf, ok)
+				}
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		index, err := strconv.Atoi(segment)
+		if err != nil || index < 0 || (typ.Kind() == reflect.Array && index >= typ.Len()) {
+			return nil, nil, false
+		}
+		steps, leaf, ok := matchFlag(typ.Elem(), rest)
+		return prepend(indexStep(index), steps, leaf, ok)
+	case reflect.Map:
+		if typ.Key().Kind() == reflect.String {
+			steps, leaf, ok := matchFlag(typ.Elem(), rest)
+			return prepend(keyStep(segment), steps, leaf, ok)
+		}
+	}
+	return nil, nil, false
+}
+
+func isBoolType(typ reflect.Type) bool {
+	for typ != nil && typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	return typ != nil && typ.Kind() == reflect.Bool
+}
+
+// 递归输出参数说明，depth用于避免递归类型无限展开
+func writeUsage(w io.Writer, typ reflect.Type, path string, tag reflect.StructTag, depth int) {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if depth > maxDepth {
+		return
+	}
+	if path != "" && isLeafType(typ) {
+		var notes []string
+		if comment := tag.Get(CommentTag); comment != "" {
+			notes = append(notes, comment)
+		}
+		if value := tag.Get(defaultTagAnchor); value != "" {
+			notes = append(notes, "(default: "+value+")")
+		}
+		if rules := tag.Get(ValidateTag); rules != "" && rules != "-" {
+			notes = append(notes, "["+rules+"]")
+		}
+		_, _ = fmt.Fprintf(w, "  --%s\t%s\t%s\n", strings.TrimSuffix(path, "."), typ.String(), strings.Join(notes, " "))
+		return
+	}
+	switch typ.Kind() {
+	case reflect.Struct:
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			fieldPath := path
+			if !field.Anonymous {
+				fieldPath = path + fieldName(field) + "."
+			}
+			writeUsage(w, field.Type, fieldPath, field.Tag, depth+1)
+		}
+	case reflect.Slice, reflect.Array:
+		writeUsage(w, typ.Elem(), path+"<n>.", tag, depth+1)
+	case reflect.Map:
+		writeUsage(w, typ.Elem(), path+"<key>.", tag, depth+1)
+	}
+}
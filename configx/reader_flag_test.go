@@ -0,0 +1,61 @@
+package configx
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type flagTestConfig struct {
+	Server struct {
+		Name string         `json:"name" comment:"服务名称"`
+		Port map[string]int `json:"port"`
+	} `json:"server"`
+	Log struct {
+		Level  string `json:"level" default:"info" validate:"oneof=debug info"`
+		Caller bool   `json:"caller"`
+	} `json:"log"`
+	Database []*struct {
+		Host string `json:"host"`
+	} `json:"database"`
+}
+
+func TestFlagReader(t *testing.T) {
+	config := &flagTestConfig{}
+	reader := NewFlagReader("serve", "--server.port.http=9000", "--log.level", "debug", "--log.caller",
+		"--database[1].host=db1", "--profile=prod", "--server.name", "demo")
+	if err := reader.Read(config); err != nil {
+		t.Fatal(err)
+	}
+	if config.Server.Port["http"] != 9000 || config.Server.Name != "demo" {
+		t.Fatalf("unexpected server: %+v", config.Server)
+	}
+	if config.Log.Level != "debug" || !config.Log.Caller {
+		t.Fatalf("unexpected log: %+v", config.Log)
+	}
+	if len(config.Database) != 2 || config.Database[1].Host != "db1" {
+		t.Fatalf("unexpected database: %+v", config.Database)
+	}
+	if origin := reader.Origins()["server.port.http"]; origin != "flag:--server.port.http" {
+		t.Fatalf("unexpected origin: %s", origin)
+	}
+
+	if err := NewFlagReader("--profile=prod").Read(&flagTestConfig{}); !errors.Is(err, ErrFlagNotMatched) {
+		t.Fatalf("expected ErrFlagNotMatched, got %v", err)
+	}
+	if err := NewFlagReader("--server.port.http=abc").Read(&flagTestConfig{}); err == nil {
+		t.Fatal("expected parse error")
+	}
+
+	var output bytes.Buffer
+	help := &FlagReader{Args: []string{"--help"}, Output: &output}
+	if err := help.Read(&flagTestConfig{}); !errors.Is(err, ErrFlagHelp) {
+		t.Fatalf("expected ErrFlagHelp, got %v", err)
+	}
+	for _, want := range []string{"--server.name", "服务名称", "--server.port.<key>", "(default: info)", "[oneof=debug info]", "--database.<n>.host"} {
+		if !strings.Contains(output.String(), want) {
+			t.Fatalf("usage missing %q:\n%s", want, output.String())
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package configx
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-xuan/utilx/errorx"
+	"github.com/go-xuan/utilx/marshalx"
+)
+
+// DefaultHttpTimeout 远程配置默认请求超时时间
+const DefaultHttpTimeout = 10 * time.Second
+
+// NewHttpReader 创建远程配置读取器，url为完整地址或者相对锚点的路径
+func NewHttpReader(url string) *HttpReader {
+	return &HttpReader{
+		Url: url,
+	}
+}
+
+// HttpReader 远程配置读取器，通过HTTP(S) GET读取配置
+// 1. 配置格式优先使用Format，其次根据响应的Content-Type以及url扩展名判断，默认为yaml
+// 2. 携带上次响应的ETag请求，服务端返回304时使用缓存的配置
+// 3. 实现 Watcher 接口，轮询间隔参考 SetWatchInterval
+type HttpReader struct {
+	Base          string            `json:"base"`          // 基础地址（锚点），Url为相对路径时拼接
+	Url           string            `json:"url"`           // 配置地址
+	Format        string            `json:"format"`        // 配置格式，例如json/yaml，为空时自动判断
+	Authorization string            `json:"authorization"` // Authorization请求头，例如 "Bearer xxx"
+	Header        map[string]string `json:"header"`        // 其他请求头
+	Timeout       time.Duration     `json:"timeout"`       // 请求超时时间，默认为 DefaultHttpTimeout
+	Client        *http.Client      `json:"-"`             // http客户端，为空时使用默认客户端
+	mu            sync.Mutex        // 缓存锁
+	data          []byte            // 配置内容缓存
+	etag          string            // 配置内容ETag
+	format        string            // 实际使用的配置格式
+}
+
+func (r *HttpReader) Anchor(base string) {
+	if r.Base == "" {
+		r.Base = base
+	}
+}
+
+func (r *HttpReader) Location() string {
+	return "http@" + r.GetUrl()
+}
+
+// Read 读取远程配置，已读取过的配置直接使用缓存，缓存由 Watch 轮询刷新
+func (r *HttpReader) Read(v any) error {
+	r.mu.Lock()
+	data, format := r.data, r.format
+	r.mu.Unlock()
+	if data == nil {
+		var err error
+		if data, format, _, err = r.fetch(context.Background()); err != nil {
+			return errorx.Wrap(err, "read http reader failed")
+		}
+	}
+	if err := marshalx.Apply(format).Unmarshal(data, v); err != nil {
+		return errorx.Wrap(err, "unmarshal http reader failed")
+	}
+	return nil
+}
+
+// Watch 轮询远程配置，配置内容变更时回调onChange
+func (r *HttpReader) Watch(onChange func()) (func(), error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		ticker := time.NewTicker(watchInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, _, changed, err := r.fetch(ctx); err == nil && changed {
+					onChange()
+				}
+			}
+		}
+	}()
+	return cancel, nil
+}
+
+// GetUrl 获取完整的配置地址
+func (r *HttpReader) GetUrl() string {
+	if r.Base == "" || strings.Contains(r.Url, "://") {
+		return r.Url
+	}
+	return strings.TrimSuffix(r.Base, "/") + "/" + strings.TrimPrefix(r.Url, "/")
+}
+
+// 请求远程配置并更新缓存，返回配置内容、格式以及配置内容是否变更
+func (r *HttpReader) fetch(ctx context.Context) ([]byte, string, bool, error) {
+	timeout := r.Timeout
+	if timeout <= 0 {
+		timeout = DefaultHttpTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.GetUrl(), nil)
+	if err != nil {
+		return nil, "", false, errorx.Wrap(err, "create request failed")
+	}
+	for key, value := range r.Header {
+		req.Header.Set(key, value)
+	}
+	if r.Authorization != "" {
+		req.Header.Set("Authorization", r.Authorization)
+	}
+	r.mu.Lock()
+	etag := r.etag
+	r.mu.Unlock()
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	client := r.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", false, errorx.Wrap(err, "request remote config failed")
+	}
+	defer resp.Body.Close()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	switch {
+	case resp.StatusCode == http.StatusNotModified && r.data != nil:
+		return r.data, r.format, false, nil
+	case resp.StatusCode != http.StatusOK:
+		return nil, "", false, errorx.Sprintf("unexpected status code: %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", false, errorx.Wrap(err, "read response body failed")
+	}
+	changed := r.data != nil && !bytes.Equal(r.data, data)
+	r.data, r.etag, r.format = data, resp.Header.Get("ETag"), r.detectFormat(resp.Header.Get("Content-Type"))
+	return r.data, r.format, changed, nil
+}
+
+// 判断配置格式，优先使用Format，其次为Content-Type以及url扩展名
+func (r *HttpReader) detectFormat(contentType string) string {
+	if r.Format != "" {
+		return r.Format
+	}
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		switch {
+		case strings.HasSuffix(mediaType, "json"):
+			return "json"
+		case strings.HasSuffix(mediaType, "yaml"), strings.HasSuffix(mediaType, "yml"):
+			return "yaml"
+		case strings.HasSuffix(mediaType, "toml"):
+			return "toml"
+		case strings.HasSuffix(mediaType, "xml"):
+			return "xml"
+		}
+	}
+	if u, err := url.Parse(r.GetUrl()); err == nil {
+		if ext := strings.TrimPrefix(path.Ext(u.Path), "."); ext != "" {
+			return ext
+		}
+	}
+	return "yaml"
+}
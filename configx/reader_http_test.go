@@ -0,0 +1,76 @@
+package configx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type httpTestConfig struct {
+	Host string `json:"host"`
+	Port int    `json:"port"`
+}
+
+func TestHttpReader(t *testing.T) {
+	var body atomic.Value
+	body.Store(`{"host":"localhost","port":3306}`)
+	var requests, notModified atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		data := body.Load().(string)
+		etag := `"` + data + `"`
+		if r.Header.Get("If-None-Match") == etag {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		_, _ = w.Write([]byte(data))
+	}))
+	defer server.Close()
+
+	if err := NewHttpReader(server.URL + "/database").Read(&httpTestConfig{}); err == nil {
+		t.Fatal("expected unauthorized error")
+	}
+
+	reader := NewHttpReader("database")
+	reader.Anchor(server.URL)
+	reader.Authorization = "Bearer token"
+	config := &httpTestConfig{}
+	if err := reader.Read(config); err != nil {
+		t.Fatal(err)
+	}
+	if config.Host != "localhost" || config.Port != 3306 || reader.Location() != "http@"+server.URL+"/database" {
+		t.Fatalf("unexpected config: %+v, location: %s", config, reader.Location())
+	}
+
+	SetWatchInterval(20 * time.Millisecond)
+	defer SetWatchInterval(3 * time.Second)
+	changed := make(chan struct{}, 1)
+	stop, err := reader.Watch(func() { changed <- struct{}{} })
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+	time.Sleep(100 * time.Millisecond)
+	if notModified.Load() == 0 {
+		t.Fatal("expected conditional requests with ETag")
+	}
+	body.Store(`{"host":"remote","port":3307}`)
+	select {
+	case <-changed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("change not detected")
+	}
+	updated := &httpTestConfig{}
+	if err = reader.Read(updated); err != nil || updated.Host != "remote" || updated.Port != 3307 {
+		t.Fatalf("unexpected updated config: %+v, err: %v", updated, err)
+	}
+}
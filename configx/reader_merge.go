@@ -0,0 +1,157 @@
+package configx
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-xuan/utilx/errorx"
+)
+
+// OriginReader 可以提供字段来源的读取器
+type OriginReader interface {
+	Origins() map[string]string // 获取各字段的来源，key为字段路径
+}
+
+// NewMergeReader 创建合并读取器，读取器按照优先级从低到高排列，例如：
+// NewMergeReader(NewTagReader(), NewFileReader("demo.yaml"), nacosx.NewReader("demo.yaml"), NewEnvReader())
+func NewMergeReader(readers ...Reader) *MergeReader {
+	return &MergeReader{Readers: readers}
+}
+
+// MergeReader 合并读取器，依次使用全部读取器读取配置，后面的读取器仅覆盖其实际设置（非零值）的字段
+// 与 ReadConfigurator 的首个有效读取器生效不同，合并读取器允许默认值、文件、nacos、环境变量等配置源同时生效
+// 合并规则：结构体逐字段合并，map逐key合并，切片以及标量非零值时整体覆盖
+// 注意：布尔值false、数值0等零值无法覆盖低优先级配置源中的非零值
+type MergeReader struct {
+	Readers []Reader          // 读取器，按照优先级从低到高排列
+	origins map[string]string // 字段来源
+}
+
+func (r *MergeReader) Anchor(anchor string) {
+	for _, reader := range r.Readers {
+		reader.Anchor(anchor)
+	}
+}
+
+func (r *MergeReader) Location() string {
+	locations := make([]string, 0, len(r.Readers))
+	for _, reader := range r.Readers {
+		locations = append(locations, reader.Location())
+	}
+	return "merge[" + strings.Join(locations, ",") + "]"
+}
+
+// Read 依次读取并合并配置，读取失败的读取器将被跳过，全部读取器读取失败时返回错误
+func (r *MergeReader) Read(v any) error {
+	target := reflect.ValueOf(v)
+	if target.Kind() != reflect.Pointer || target.IsNil() {
+		return errorx.New("the merge target must be a non-nil pointer")
+	}
+	origins := make(map[string]string)
+	var errs []string
+	for _, reader := range r.Readers {
+		// 读取至新的实例，仅合并当前读取器实际设置的字段
+		layer := reflect.New(target.Elem().Type())
+		if err := ReaderRead(reader, layer.Interface()); err != nil {
+			errs = append(errs, reader.Location()+": "+err.Error())
+			continue
+		}
+		mergeField(target.Elem(), layer.Elem(), "", reader.Location(), origins)
+	}
+	if len(errs) == len(r.Readers) {
+		return errorx.New("all readers failed: " + strings.Join(errs, "; "))
+	}
+	r.origins = origins
+	return nil
+}
+
+// Origins 获取各字段的最终来源，key为字段路径
+func (r *MergeReader) Origins() map[string]string {
+	return r.origins
+}
+
+// Watch 监听全部支持监听的读取器，任一读取器的配置变更时回调onChange
+func (r *MergeReader) Watch(onChange func()) (func(), error) {
+	var stops []func()
+	stopAll := func() {
+		for _, stop := range stops {
+			stop()
+		}
+	}
+	for _, reader := range r.Readers {
+		if watcher, ok := reader.(Watcher); ok {
+			stop, err := watcher.Watch(onChange)
+			if err != nil {
+				stopAll()
+				return nil, errorx.Wrap(err, "watch reader failed: "+reader.Location())
+			}
+			stops = append(stops, stop)
+		}
+	}
+	return stopAll, nil
+}
+
+// 将src中的非零值合并至dst
+func mergeField(dst, src reflect.Value, path, location string, origins map[string]string) {
+	switch src.Kind() {
+	case reflect.Struct:
+		typ := src.Type()
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			fieldPath := path
+			if !field.Anonymous {
+				fieldPath = joinPath(path, fieldName(field))
+			}
+			mergeField(dst.Field(i), src.Field(i), fieldPath, location, origins)
+		}
+	case reflect.Pointer:
+		if src.IsNil() {
+			return
+		}
+		if src.Elem().Kind() == reflect.Struct {
+			if dst.IsNil() {
+				dst.Set(reflect.New(src.Type().Elem()))
+			}
+			mergeField(dst.Elem(), src.Elem(), path, location, origins)
+			return
+		}
+		dst.Set(src)
+		clearOrigins(origins, path)
+		origins[path] = location
+	case reflect.Map:
+		if src.Len() == 0 {
+			return
+		}
+		if dst.IsNil() {
+			dst.Set(reflect.MakeMapWithSize(src.Type(), src.Len()))
+		}
+		iter := src.MapRange()
+		for iter.Next() {
+			dst.SetMapIndex(iter.Key(), iter.Value())
+			keyPath := joinPath(path, fmt.Sprint(iter.Key().Interface()))
+			clearOrigins(origins, keyPath)
+			origins[keyPath] = location
+		}
+	default:
+		if src.IsZero() {
+			return
+		}
+		dst.Set(src)
+		clearOrigins(origins, path)
+		origins[path] = location
+	}
+}
+
+// 字段名称，优先使用json标签
+func fieldName(field reflect.StructField) string {
+	if tag := field.Tag.Get("json"); tag != "" && tag != "-" {
+		if name, _, _ := strings.Cut(tag, ","); name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
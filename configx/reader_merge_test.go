@@ -0,0 +1,53 @@
+package configx
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type mergeTest struct {
+	Host    string            `json:"host" default:"localhost"`
+	Port    int               `json:"port" default:"8080"`
+	Options map[string]string `json:"options"`
+	Nested  *struct {
+		Password string `json:"password"`
+		Timeout  int    `json:"timeout"`
+	} `json:"nested"`
+}
+
+func TestMergeReader(t *testing.T) {
+	dir := t.TempDir()
+	_ = os.WriteFile(filepath.Join(dir, "base.json"), []byte(`{"host":"db","options":{"a":"1"},"nested":{"timeout":3}}`), 0644)
+	_ = os.WriteFile(filepath.Join(dir, "secret.json"), []byte(`{"options":{"b":"2"},"nested":{"password":"123"}}`), 0644)
+
+	base := &FileReader{Dir: dir, Name: "base.json"}
+	secret := &FileReader{Dir: dir, Name: "secret.json"}
+	reader := NewMergeReader(NewTagReader(), base, secret, &FileReader{Dir: dir, Name: "missing.json"})
+	var config = &mergeTest{}
+	if err := reader.Read(config); err != nil {
+		t.Fatal(err)
+	}
+	if config.Host != "db" || config.Port != 8080 {
+		t.Errorf("unexpected host/port: %s/%d", config.Host, config.Port)
+	}
+	if config.Options["a"] != "1" || config.Options["b"] != "2" {
+		t.Errorf("unexpected options: %v", config.Options)
+	}
+	if config.Nested == nil || config.Nested.Password != "123" || config.Nested.Timeout != 3 {
+		t.Errorf("unexpected nested: %+v", config.Nested)
+	}
+	origins := reader.Origins()
+	expects := map[string]string{
+		"host":            base.Location(),
+		"port":            NewTagReader().Location(),
+		"options.b":       secret.Location(),
+		"nested.password": secret.Location(),
+		"nested.timeout":  base.Location(),
+	}
+	for path, location := range expects {
+		if origins[path] != location {
+			t.Errorf("origin of %s: expected %s, got %s", path, location, origins[path])
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package configx
+
+import (
+	"io"
+	"reflect"
+
+	"github.com/go-xuan/typex"
+	"github.com/go-xuan/utilx/errorx"
+)
+
+// Builder 客户端构造函数，根据配置创建客户端
+type Builder[T any, C io.Closer] func(config T) (C, error)
+
+// NewRegistry 创建客户端注册中心，name为客户端类型名称（用于错误信息），driver为默认驱动
+func NewRegistry[T any, C io.Closer](name, driver string) *Registry[T, C] {
+	return &Registry[T, C]{
+		name:     name,
+		driver:   driver,
+		builders: typex.NewStringEnum[Builder[T, C]](),
+		pool:     NewPool[C](),
+	}
+}
+
+// Registry 客户端注册中心，管理客户端驱动的构造函数以及各数据源的客户端
+// 新增客户端驱动时仅需注册构造函数，配置中的驱动名称为空时使用默认驱动
+type Registry[T any, C io.Closer] struct {
+	name     string                             // 客户端类型名称，例如cache/database
+	driver   string                             // 默认驱动
+	builders *typex.Enum[string, Builder[T, C]] // 驱动构造函数
+	pool     *Pool[C]                           // 客户端池
+}
+
+// Register 注册驱动构造函数，重复注册时覆盖
+func (r *Registry[T, C]) Register(driver string, builder Builder[T, C]) {
+	if builder != nil {
+		r.builders.Add(driver, builder)
+	}
+}
+
+// Drivers 按照注册顺序返回已注册的驱动名称
+func (r *Registry[T, C]) Drivers() []string {
+	return r.builders.Keys()
+}
+
+// Build 使用指定驱动创建客户端，driver为空时使用默认驱动
+func (r *Registry[T, C]) Build(driver string, config T) (C, error) {
+	if driver == "" {
+		driver = r.driver
+	}
+	builder, ok := r.builders.Find(driver)
+	if !ok {
+		var zero C
+		return zero, errorx.Sprintf("%s client driver is not registered: %s", r.name, driver)
+	}
+	client, err := builder(config)
+	if err != nil {
+		return client, errorx.Wrap(err, "build "+r.name+" client failed")
+	} else if isNil(client) {
+		return client, errorx.Sprintf("%s client driver returned nil client: %s", r.name, driver)
+	}
+	return client, nil
+}
+
+// Pool 获取客户端池
+func (r *Registry[T, C]) Pool() *Pool[C] {
+	return r.pool
+}
+
+// Add 添加客户端，客户端为nil时忽略
+func (r *Registry[T, C]) Add(source string, client C) {
+	if !isNil(client) {
+		r.pool.Add(source, client)
+	}
+}
+
+// Get 获取客户端，未指定 source 时返回 default，数据源不存在时返回错误
+func (r *Registry[T, C]) Get(source ...string) (C, error) {
+	client, err := r.pool.Get(source...)
+	if err != nil {
+		return client, errorx.Wrap(err, r.name+" client not found")
+	}
+	return client, nil
+}
+
+// MustGet 获取客户端，客户端池未初始化或者数据源不存在时panic
+func (r *Registry[T, C]) MustGet(source ...string) C {
+	if !r.pool.Initialized() {
+		panic(r.name + " client pool not initialized")
+	}
+	client, err := r.Get(source...)
+	if err != nil {
+		panic(err)
+	}
+	return client
+}
+
+// Sources 返回全部数据源名称
+func (r *Registry[T, C]) Sources() []string {
+	return r.pool.Sources()
+}
+
+// Close 按照添加顺序的逆序关闭全部客户端
+func (r *Registry[T, C]) Close() error {
+	return r.pool.Close()
+}
+
+// 判断客户端是否为nil，包括值为nil的指针以及接口
+func isNil(v any) bool {
+	if v == nil {
+		return true
+	}
+	switch val := reflect.ValueOf(v); val.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		return val.IsNil()
+	default:
+		return false
+	}
+}
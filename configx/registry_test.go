@@ -0,0 +1,70 @@
+package configx
+
+import (
+	"reflect"
+	"testing"
+)
+
+type registryTestConfig struct {
+	Source string
+	Driver string
+}
+
+type registryTestClient struct {
+	source string
+	closed *[]string
+}
+
+func (c *registryTestClient) Close() error {
+	*c.closed = append(*c.closed, c.source)
+	return nil
+}
+
+func TestRegistry(t *testing.T) {
+	var closed []string
+	registry := NewRegistry[*registryTestConfig, *registryTestClient]("test", "mock")
+	registry.Register("mock", func(config *registryTestConfig) (*registryTestClient, error) {
+		return &registryTestClient{source: config.Source, closed: &closed}, nil
+	})
+	registry.Register("nil", func(*registryTestConfig) (*registryTestClient, error) {
+		return nil, nil
+	})
+
+	if _, err := registry.Build("unknown", &registryTestConfig{}); err == nil {
+		t.Fatal("expected error for unregistered driver")
+	}
+	if _, err := registry.Build("nil", &registryTestConfig{}); err == nil {
+		t.Fatal("expected error for nil client")
+	}
+	for _, source := range []string{"db1", "db2", "db3"} {
+		client, err := registry.Build("", &registryTestConfig{Source: source})
+		if err != nil {
+			t.Fatal(err)
+		}
+		registry.Add(source, client)
+	}
+	registry.Add("db4", nil)
+
+	if got := registry.Sources(); !reflect.DeepEqual(got, []string{"db1", "db2", "db3"}) {
+		t.Fatalf("unexpected sources: %v", got)
+	}
+	if client, err := registry.Get(); err != nil || client.source != "db1" {
+		t.Fatalf("default should be the first source: %v %v", client, err)
+	}
+	if client, err := registry.Get("db2"); err != nil || client.source != "db2" {
+		t.Fatalf("unexpected client: %v %v", client, err)
+	}
+	if _, err := registry.Get("missing"); err == nil {
+		t.Fatal("expected error for unknown source")
+	}
+
+	if err := registry.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(closed, []string{"db3", "db2", "db1"}) {
+		t.Fatalf("clients should be closed in reverse order: %v", closed)
+	}
+	if registry.Pool().Initialized() {
+		t.Fatal("pool should be empty after close")
+	}
+}
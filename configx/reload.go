@@ -0,0 +1,113 @@
+package configx
+
+import (
+	"reflect"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// 默认的配置监听集合
+var defaultWatchers = NewWatchers()
+
+// Reloadable 支持热加载的配置器
+// 配置变更时框架会将变更后的配置读取至新的配置器实例，并调用变更前配置器的OnChange方法，
+// 不会修改变更前配置器的字段，因此并发读取配置时不会读取到写入一半的配置，
+// 实现者需要在OnChange中以原子替换的方式使新配置生效（例如替换全局配置指针、替换客户端池中的客户端）
+// OnChange返回错误时本次变更将被丢弃，继续使用变更前的配置
+type Reloadable interface {
+	Configurator
+	OnChange(old, updated Configurator) error
+}
+
+// Watcher 支持监听配置变更的读取器
+type Watcher interface {
+	// Watch 监听配置变更，配置变更时调用onChange，返回停止监听函数
+	Watch(onChange func()) (stop func(), err error)
+}
+
+// StopWatchers 停止默认配置监听集合中的全部配置监听
+func StopWatchers() {
+	defaultWatchers.Stop()
+}
+
+// NewWatchers 创建配置监听集合，用于隔离不同应用（例如同一进程中的多个Engine）的配置监听
+func NewWatchers() *Watchers {
+	return &Watchers{stops: make(map[string]func())}
+}
+
+// DefaultWatchers 获取默认的配置监听集合，LoadConfigurator 以及 LoadConfiguratorWith 的配置监听添加到该集合
+func DefaultWatchers() *Watchers {
+	return defaultWatchers
+}
+
+// Watchers 配置监听集合
+type Watchers struct {
+	mu    sync.Mutex
+	stops map[string]func() // 配置监听停止函数，key为配置器类型@配置位置
+}
+
+// Stop 停止全部配置监听
+func (w *Watchers) Stop() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for key, stop := range w.stops {
+		stop()
+		delete(w.stops, key)
+	}
+}
+
+// 监听配置器，配置变更时重新读取配置并回调OnChange
+// 同一集合中同一类型的配置器在同一位置仅保留最新的监听
+func (w *Watchers) watch(configurator Configurator, reader Reader) error {
+	current, ok := configurator.(Reloadable)
+	if !ok || reader == nil {
+		return nil
+	}
+	watcher, ok := reader.(Watcher)
+	if !ok {
+		return nil
+	}
+	typ := reflect.TypeOf(configurator)
+	if typ.Kind() != reflect.Pointer {
+		return nil
+	}
+	key := typ.String() + "@" + reader.Location()
+	logger := log.WithField("configurator", key)
+
+	var mu sync.Mutex
+	stop, err := watcher.Watch(func() {
+		mu.Lock()
+		defer mu.Unlock()
+		// 读取至新的配置器实例，变更前的配置保持不变
+		updated, _ := reflect.New(typ.Elem()).Interface().(Reloadable)
+		if err := ReaderRead(reader, updated); err != nil {
+			logger.WithError(err).Warn("reload configurator failed")
+			return
+		} else if fields := validateWithReader(updated, reader); len(fields) > 0 {
+			logger.WithError(&ValidationError{Fields: fields}).Warn("reloaded configurator is invalid, change discarded")
+			return
+		} else if !updated.Valid() {
+			logger.Warn("reloaded configurator is invalid, change discarded")
+			return
+		}
+		if err := current.OnChange(current, updated); err != nil {
+			logger.WithError(err).Error("apply configurator change failed, change discarded")
+			return
+		}
+		current = updated
+		logger.Info("reload configurator success")
+	})
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if previous, exist := w.stops[key]; exist {
+		previous()
+	}
+	w.stops[key] = stop
+	logger.Debug("watch configurator success")
+	return nil
+}
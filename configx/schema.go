@@ -0,0 +1,352 @@
+package configx
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	SchemaDraft = "https://json-schema.org/draft-07/schema#" // JSON Schema版本
+	CommentTag  = "comment"                                  // 字段说明标签
+	maxDepth    = 8                                          // 递归类型的最大展开层级
+)
+
+// Schema JSON Schema，用于IDE校验以及补全配置文件
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Default              any                `json:"default,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties any                `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+}
+
+// JSON 格式化输出
+func (s *Schema) JSON() ([]byte, error) {
+	return json.MarshalIndent(s, "", "  ")
+}
+
+// GenerateSchema 根据配置结构体生成JSON Schema，字段名称优先使用yaml标签
+// 类型取自字段类型，说明取自comment标签，默认值取自default标签，约束取自validate标签（参考 ValidateTag）
+// 传入多个配置结构体时生成oneOf，例如 GenerateSchema(&dbx.Config{}, &dbx.Configs{}) 表示单数据源或者多数据源
+func GenerateSchema(values ...any) *Schema {
+	var schema *Schema
+	if len(values) == 1 {
+		schema = typeSchema(reflect.TypeOf(values[0]), 0)
+	} else {
+		schema = &Schema{}
+		for _, v := range values {
+			schema.OneOf = append(schema.OneOf, typeSchema(reflect.TypeOf(v), 0))
+		}
+	}
+	schema.Schema = SchemaDraft
+	if len(values) > 0 {
+		schema.Title = derefType(reflect.TypeOf(values[0])).String()
+	}
+	return schema
+}
+
+// GenerateExample 根据配置结构体生成带注释的yaml示例，字段值使用default标签，注释包含字段说明以及校验规则
+func GenerateExample(v any) []byte {
+	var sb strings.Builder
+	typ := derefType(reflect.TypeOf(v))
+	switch typ.Kind() {
+	case reflect.Struct:
+		writeStructExample(&sb, typ, "", 0)
+	case reflect.Slice, reflect.Array:
+		writeListExample(&sb, typ.Elem(), "", 0)
+	default:
+		sb.WriteString(exampleValue(typ, "") + "\n")
+	}
+	return []byte(sb.String())
+}
+
+// FileName 获取配置器读取的配置文件名称，例如 database.yaml，没有文件读取器时返回空字符串
+func FileName(configurator Configurator) string {
+	var find func(readers []Reader) string
+	find = func(readers []Reader) string {
+		for _, reader := range readers {
+			switch r := reader.(type) {
+			case *FileReader:
+				return r.Name
+			case *MergeReader:
+				if name := find(r.Readers); name != "" {
+					return name
+				}
+			}
+		}
+		return ""
+	}
+	if configurator == nil {
+		return ""
+	}
+	return find(configurator.Readers())
+}
+
+// 字段名称，配置文件以yaml为主，优先使用yaml标签
+func yamlName(field reflect.StructField) string {
+	if tag := field.Tag.Get("yaml"); tag != "" && tag != "-" {
+		if name, _, _ := strings.Cut(tag, ","); name != "" {
+			return name
+		}
+	}
+	return fieldName(field)
+}
+
+// 是否忽略字段
+func skipField(field reflect.StructField) bool {
+	return !field.IsExported() || field.Tag.Get("yaml") == "-" || (field.Tag.Get("yaml") == "" && field.Tag.Get("json") == "-")
+}
+
+func derefType(typ reflect.Type) reflect.Type {
+	for typ != nil && typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	return typ
+}
+
+// 根据类型生成Schema
+func typeSchema(typ reflect.Type, depth int) *Schema {
+	typ = derefType(typ)
+	schema := &Schema{}
+	if depth > maxDepth {
+		return schema
+	}
+	if typ == reflect.TypeOf(time.Duration(0)) {
+		schema.Type = "string"
+		schema.Pattern = durationPattern
+		return schema
+	}
+	switch typ.Kind() {
+	case reflect.String:
+		schema.Type = "string"
+	case reflect.Bool:
+		schema.Type = "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		schema.Type = "integer"
+	case reflect.Float32, reflect.Float64:
+		schema.Type = "number"
+	case reflect.Slice, reflect.Array:
+		schema.Type = "array"
+		schema.Items = typeSchema(typ.Elem(), depth+1)
+	case reflect.Map:
+		schema.Type = "object"
+		schema.AdditionalProperties = typeSchema(typ.Elem(), depth+1)
+	case reflect.Struct:
+		schema.Type = "object"
+		schema.Properties = make(map[string]*Schema)
+		schema.AdditionalProperties = false
+		addProperties(schema, typ, depth)
+	}
+	return schema
+}
+
+// 添加结构体字段，匿名嵌入的结构体字段展开至当前层级
+func addProperties(schema *Schema, typ reflect.Type, depth int) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if skipField(field) {
+			continue
+		}
+		if field.Anonymous && derefType(field.Type).Kind() == reflect.Struct {
+			addProperties(schema, derefType(field.Type), depth)
+			continue
+		}
+		name := yamlName(field)
+		property := typeSchema(field.Type, depth+1)
+		property.Description = field.Tag.Get(CommentTag)
+		if value := field.Tag.Get(defaultTagAnchor); value != "" {
+			property.Default = parseTagValue(field.Type, value)
+		}
+		if applyRules(property, field) {
+			schema.Required = append(schema.Required, name)
+		}
+		schema.Properties[name] = property
+	}
+}
+
+// 根据validate标签添加约束，返回字段是否必填
+func applyRules(schema *Schema, field reflect.StructField) bool {
+	tag := field.Tag.Get(ValidateTag)
+	if tag == "" || tag == "-" {
+		return false
+	}
+	var required bool
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		switch name {
+		case "required":
+			required = true
+		case "min", "max":
+			limit, err := strconv.ParseFloat(param, 64)
+			if err != nil {
+				continue
+			}
+			switch schema.Type {
+			case "string":
+				n := int(limit)
+				if name == "min" {
+					schema.MinLength = &n
+				} else {
+					schema.MaxLength = &n
+				}
+			case "array":
+				n := int(limit)
+				if name == "min" {
+					schema.MinItems = &n
+				} else {
+					schema.MaxItems = &n
+				}
+			case "integer", "number":
+				if name == "min" {
+					schema.Minimum = &limit
+				} else {
+					schema.Maximum = &limit
+				}
+			}
+		case "oneof":
+			for _, option := range strings.Fields(param) {
+				schema.Enum = append(schema.Enum, parseTagValue(field.Type, option))
+			}
+		case "url":
+			schema.Format = "uri"
+		case "hostport":
+			schema.Pattern = `^[^,:\s]+:\d{1,5}(\s*,\s*[^,:\s]+:\d{1,5})*$`
+		case "duration":
+			schema.Pattern = durationPattern
+		}
+	}
+	return required
+}
+
+// 时间间隔格式，例如 10s、1m30s
+const durationPattern = `^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+
+// 将标签值转换为字段类型对应的值，转换失败时返回原字符串
+func parseTagValue(typ reflect.Type, value string) any {
+	typ = derefType(typ)
+	if typ == reflect.TypeOf(time.Duration(0)) {
+		return value
+	}
+	switch typ.Kind() {
+	case reflect.Bool:
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return n
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n, err := strconv.ParseUint(value, 10, 64); err == nil {
+			return n
+		}
+	case reflect.Float32, reflect.Float64:
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	}
+	return value
+}
+
+// 输出结构体示例
+func writeStructExample(sb *strings.Builder, typ reflect.Type, indent string, depth int) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if skipField(field) {
+			continue
+		}
+		fieldType := derefType(field.Type)
+		if field.Anonymous && fieldType.Kind() == reflect.Struct {
+			writeStructExample(sb, fieldType, indent, depth)
+			continue
+		}
+		if comment := exampleComment(field); comment != "" {
+			sb.WriteString(indent + "# " + comment + "\n")
+		}
+		name := yamlName(field)
+		switch {
+		case fieldType.Kind() == reflect.Struct && fieldType != reflect.TypeOf(time.Time{}) && depth < maxDepth:
+			sb.WriteString(indent + name + ":\n")
+			writeStructExample(sb, fieldType, indent+"  ", depth+1)
+		case (fieldType.Kind() == reflect.Slice || fieldType.Kind() == reflect.Array) &&
+			derefType(fieldType.Elem()).Kind() == reflect.Struct && depth < maxDepth:
+			sb.WriteString(indent + name + ":\n")
+			writeListExample(sb, fieldType.Elem(), indent, depth+1)
+		default:
+			sb.WriteString(indent + name + ": " + exampleValue(fieldType, field.Tag.Get(defaultTagAnchor)) + "\n")
+		}
+	}
+}
+
+// 输出列表示例，列表包含一个元素
+func writeListExample(sb *strings.Builder, elem reflect.Type, indent string, depth int) {
+	elem = derefType(elem)
+	if elem.Kind() != reflect.Struct {
+		sb.WriteString(indent + "- " + exampleValue(elem, "") + "\n")
+		return
+	}
+	var item strings.Builder
+	writeStructExample(&item, elem, indent+"  ", depth)
+	// 将首行的缩进替换为列表标识
+	sb.WriteString(indent + "- " + strings.TrimPrefix(item.String(), indent+"  "))
+}
+
+// 字段示例值
+func exampleValue(typ reflect.Type, value string) string {
+	typ = derefType(typ)
+	switch typ.Kind() {
+	case reflect.Slice, reflect.Array:
+		return "[]"
+	case reflect.Map, reflect.Struct:
+		return "{}"
+	case reflect.String:
+		if value == "" || strings.ContainsAny(value, ":#{}[],&*?|<>=!%@`'\"") || value != strings.TrimSpace(value) {
+			return strconv.Quote(value)
+		}
+		return value
+	case reflect.Bool:
+		if value == "" {
+			return "false"
+		}
+	case reflect.Interface:
+		if value == "" {
+			return "null"
+		}
+	default:
+		if value == "" {
+			if typ == reflect.TypeOf(time.Duration(0)) {
+				return "0s"
+			}
+			return "0"
+		}
+	}
+	return value
+}
+
+// 字段注释，包含字段说明以及校验规则
+func exampleComment(field reflect.StructField) string {
+	comment := field.Tag.Get(CommentTag)
+	if rules := field.Tag.Get(ValidateTag); rules != "" && rules != "-" {
+		comment = strings.TrimSpace(fmt.Sprintf("%s [%s]", comment, rules))
+	}
+	return comment
+}
@@ -0,0 +1,80 @@
+package configx
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+type schemaTestItem struct {
+	Host string `json:"host" yaml:"host" default:"localhost" comment:"主机" validate:"required"`
+	Port int    `json:"port" yaml:"port" default:"6379" validate:"min=1,max=65535"`
+}
+
+type schemaTestConfig struct {
+	Name    string            `json:"name" yaml:"name" comment:"名称"`
+	Mode    int               `json:"mode" yaml:"mode" validate:"oneof=0 1 2" comment:"模式"`
+	Key     string            `json:"accessKey" yaml:"access_key"`
+	Enable  bool              `json:"enable" yaml:"enable" default:"true"`
+	Items   []*schemaTestItem `json:"items" yaml:"items"`
+	Options map[string]string `json:"options" yaml:"options"`
+	Ignored string            `json:"-" yaml:"-"`
+}
+
+func TestGenerateSchema(t *testing.T) {
+	schema := GenerateSchema(&schemaTestConfig{})
+	data, err := schema.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result map[string]any
+	if err = json.Unmarshal(data, &result); err != nil {
+		t.Fatal(err)
+	}
+	if result["$schema"] != SchemaDraft || result["type"] != "object" || result["additionalProperties"] != false {
+		t.Fatalf("unexpected schema: %s", data)
+	}
+	properties := result["properties"].(map[string]any)
+	if _, ok := properties["access_key"]; !ok {
+		t.Fatalf("yaml name is not used: %s", data)
+	}
+	if _, ok := properties["Ignored"]; ok {
+		t.Fatalf("ignored field is exported: %s", data)
+	}
+	mode := properties["mode"].(map[string]any)
+	if mode["description"] != "模式" || len(mode["enum"].([]any)) != 3 || mode["enum"].([]any)[2] != float64(2) {
+		t.Fatalf("unexpected mode schema: %v", mode)
+	}
+	if properties["enable"].(map[string]any)["default"] != true {
+		t.Fatalf("unexpected enable schema: %v", properties["enable"])
+	}
+	item := properties["items"].(map[string]any)["items"].(map[string]any)
+	if required := item["required"].([]any); len(required) != 1 || required[0] != "host" {
+		t.Fatalf("unexpected item schema: %v", item)
+	}
+	if port := item["properties"].(map[string]any)["port"].(map[string]any); port["maximum"] != float64(65535) || port["default"] != float64(6379) {
+		t.Fatalf("unexpected port schema: %v", port)
+	}
+	if oneOf := GenerateSchema(&schemaTestItem{}, &[]*schemaTestItem{}); len(oneOf.OneOf) != 2 || oneOf.OneOf[1].Type != "array" {
+		t.Fatalf("unexpected oneOf schema: %+v", oneOf)
+	}
+}
+
+func TestGenerateExample(t *testing.T) {
+	example := string(GenerateExample(&schemaTestConfig{}))
+	for _, want := range []string{
+		"# 名称\nname: \"\"\n",
+		"# 模式 [oneof=0 1 2]\nmode: 0\n",
+		"enable: true\n",
+		"items:\n- # 主机 [required]\n  host: localhost\n  # [min=1,max=65535]\n  port: 6379\n",
+		"options: {}\n",
+	} {
+		if !strings.Contains(example, want) {
+			t.Fatalf("example missing %q:\n%s", want, example)
+		}
+	}
+	list := string(GenerateExample(&[]schemaTestItem{}))
+	if !strings.HasPrefix(list, "- # 主机 [required]\n  host: localhost\n") {
+		t.Fatalf("unexpected list example:\n%s", list)
+	}
+}
@@ -0,0 +1,427 @@
+package configx
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-xuan/typex"
+	"github.com/go-xuan/utilx/errorx"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	SecretKeyEnv  = "QUANX_SECRET_KEY" // ENC(...)加密值的密钥环境变量，密钥为16/24/32字节或者其base64编码
+	SecretTag     = "secret"           // 敏感字段标签，例如 `secret:"true"`，字段中占位符解析后的值将被脱敏
+	MaskedSecret  = "******"           // 脱敏后的值
+	minMaskLength = 4                  // 子串脱敏的最小密钥长度，过短的密钥仅在完全相等时脱敏，避免误伤日志内容
+)
+
+var (
+	secretResolvers = typex.NewStringEnum[SecretResolver]()               // 密钥解析器，key为占位符scheme
+	secretPattern   = regexp.MustCompile(`\$\{([a-zA-Z][\w-]*):([^}]*)}`) // 占位符，例如 ${env:DB_PASS}
+	encPattern      = regexp.MustCompile(`^ENC\((.*)\)$`)                 // 加密值，例如 ENC(base64)
+	secrets         = make(map[string]struct{})                           // 已解析的密钥值，用于脱敏
+	secretReplacer  *strings.Replacer                                     // 密钥子串脱敏，密钥变更时重建
+	secretMu        sync.RWMutex
+	secretHookOnce  sync.Once
+)
+
+func init() {
+	RegisterSecretResolver("env", SecretResolverFunc(resolveEnv))
+	RegisterSecretResolver("file", SecretResolverFunc(resolveFile))
+}
+
+// InstallSecretHook 安装日志脱敏钩子，对日志内容以及字段中已解析的密钥脱敏，重复调用仅安装一次
+func InstallSecretHook() {
+	secretHookOnce.Do(func() {
+		log.AddHook(secretHook{})
+	})
+}
+
+// SecretResolver 密钥解析器，用于解析配置中形如 ${scheme:ref} 的占位符
+type SecretResolver interface {
+	Resolve(ref string) (string, error)
+}
+
+// SecretResolverFunc 密钥解析函数
+type SecretResolverFunc func(ref string) (string, error)
+
+func (f SecretResolverFunc) Resolve(ref string) (string, error) {
+	return f(ref)
+}
+
+// RegisterSecretResolver 注册密钥解析器，例如接入vault、kms等密钥管理服务
+// 默认支持 ${env:NAME} 读取环境变量、${file:/path} 读取文件内容
+func RegisterSecretResolver(scheme string, resolver SecretResolver) {
+	if scheme != "" && resolver != nil {
+		secretResolvers.Add(scheme, resolver)
+	}
+}
+
+// ResolveSecrets 解析配置中全部字符串字段的密钥占位符以及ENC(...)加密值，
+// ENC(...)解密后的值以及 SecretTag 标记字段中占位符解析后的值将被记录，MaskSecret 以及日志脱敏钩子（参考 InstallSecretHook）自动脱敏
+// 未注册scheme的占位符保持原样
+func ResolveSecrets(v any) error {
+	val := reflect.ValueOf(v)
+	if val.Kind() != reflect.Pointer || val.IsNil() {
+		return nil
+	}
+	var errs []string
+	resolveValue(val.Elem(), "", false, &errs)
+	if len(errs) > 0 {
+		return errorx.New("resolve secrets failed: " + strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// EncryptSecret 使用 SecretKeyEnv 指定的密钥加密明文，返回可直接写入配置的 ENC(...) 值
+func EncryptSecret(plaintext string) (string, error) {
+	gcm, err := secretCipher()
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return "", errorx.Wrap(err, "generate nonce failed")
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return "ENC(" + base64.StdEncoding.EncodeToString(sealed) + ")", nil
+}
+
+// DecryptSecret 解密 ENC(...) 值，也可以直接传入括号内的base64密文
+func DecryptSecret(ciphertext string) (string, error) {
+	if match := encPattern.FindStringSubmatch(strings.TrimSpace(ciphertext)); match != nil {
+		ciphertext = match[1]
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(ciphertext))
+	if err != nil {
+		return "", errorx.Wrap(err, "decode ciphertext failed")
+	}
+	gcm, err := secretCipher()
+	if err != nil {
+		return "", err
+	}
+	if len(data) < gcm.NonceSize() {
+		return "", errorx.New("ciphertext is too short")
+	}
+	nonce, sealed := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return "", errorx.Wrap(err, "decrypt ciphertext failed")
+	}
+	return string(plaintext), nil
+}
+
+// IsSecret 是否包含已解析的密钥
+func IsSecret(s string) bool {
+	return MaskSecret(s) != s
+}
+
+// MaskSecret 将字符串中已解析的密钥替换为 MaskedSecret
+func MaskSecret(s string) string {
+	if s == "" {
+		return s
+	}
+	secretMu.RLock()
+	defer secretMu.RUnlock()
+	if len(secrets) == 0 {
+		return s
+	}
+	if _, ok := secrets[s]; ok {
+		return MaskedSecret
+	}
+	if secretReplacer != nil {
+		return secretReplacer.Replace(s)
+	}
+	return s
+}
+
+// MaskFields 将配置转换为通用结构（字段名称使用json标签），SecretTag 标记的非空字段替换为 MaskedSecret，
+// 其他字符串中已解析的密钥通过 MaskSecret 脱敏，不会修改原配置
+func MaskFields(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, errorx.Wrap(err, "marshal config failed")
+	}
+	var result any
+	if err = json.Unmarshal(data, &result); err != nil {
+		return nil, errorx.Wrap(err, "unmarshal config failed")
+	}
+	return maskValue(reflect.ValueOf(v), result, false), nil
+}
+
+// 对照配置结构递归脱敏通用结构，secret为是否处于 SecretTag 标记的字段中
+func maskValue(val reflect.Value, data any, secret bool) any {
+	if data == nil {
+		return nil
+	} else if secret && data != "" {
+		return MaskedSecret
+	}
+	switch val.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !val.IsNil() {
+			return maskValue(val.Elem(), data, secret)
+		}
+	case reflect.Slice, reflect.Array:
+		if items, ok := data.([]any); ok {
+			for i := 0; i < val.Len() && i < len(items); i++ {
+				items[i] = maskValue(val.Index(i), items[i], secret)
+			}
+		}
+	case reflect.Map:
+		if items, ok := data.(map[string]any); ok {
+			iter := val.MapRange()
+			for iter.Next() {
+				if key := fmt.Sprint(iter.Key().Interface()); items[key] != nil {
+					items[key] = maskValue(iter.Value(), items[key], secret)
+				}
+			}
+		}
+	case reflect.Struct:
+		items, ok := data.(map[string]any)
+		if !ok {
+			break
+		}
+		typ := val.Type()
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			// 未导出的匿名字段中的导出字段同样会被序列化
+			if (!field.IsExported() && !field.Anonymous) || field.Tag.Get("json") == "-" {
+				continue
+			}
+			fieldSecret := secret || field.Tag.Get(SecretTag) == "true"
+			if field.Anonymous && field.Tag.Get("json") == "" {
+				// 匿名字段展开至当前层级
+				maskValue(val.Field(i), items, fieldSecret)
+			} else if name := fieldName(field); items[name] != nil {
+				items[name] = maskValue(val.Field(i), items[name], fieldSecret)
+			}
+		}
+	}
+	if s, ok := data.(string); ok {
+		return MaskSecret(s)
+	}
+	return data
+}
+
+// 记录已解析的密钥，并重建子串脱敏的替换器
+func addSecret(secret string) {
+	if secret == "" {
+		return
+	}
+	secretMu.Lock()
+	defer secretMu.Unlock()
+	if _, ok := secrets[secret]; ok {
+		return
+	}
+	secrets[secret] = struct{}{}
+	var olds []string
+	for value := range secrets {
+		if len(value) >= minMaskLength {
+			olds = append(olds, value)
+		}
+	}
+	if len(olds) == 0 {
+		return
+	}
+	// 优先替换较长的密钥，避免较短的密钥是其子串时仅部分脱敏
+	sort.Slice(olds, func(i, j int) bool { return len(olds[i]) > len(olds[j]) })
+	pairs := make([]string, 0, len(olds)*2)
+	for _, old := range olds {
+		pairs = append(pairs, old, MaskedSecret)
+	}
+	secretReplacer = strings.NewReplacer(pairs...)
+}
+
+// 递归解析字符串字段，secret为是否处于 SecretTag 标记的字段中
+func resolveValue(val reflect.Value, path string, secret bool, errs *[]string) {
+	switch val.Kind() {
+	case reflect.String:
+		if !val.CanSet() {
+			return
+		}
+		if resolved, ok, err := resolveString(val.String(), secret); err != nil {
+			*errs = append(*errs, fmt.Sprintf("%s: %v", path, err))
+		} else if ok {
+			val.SetString(resolved)
+		}
+	case reflect.Pointer:
+		if !val.IsNil() {
+			resolveValue(val.Elem(), path, secret, errs)
+		}
+	case reflect.Interface:
+		if val.IsNil() || !val.CanSet() {
+			return
+		}
+		// 接口内的值不可寻址，复制后解析再写回
+		elem := reflect.New(val.Elem().Type()).Elem()
+		elem.Set(val.Elem())
+		resolveValue(elem, path, secret, errs)
+		val.Set(elem)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < val.Len(); i++ {
+			resolveValue(val.Index(i), fmt.Sprintf("%s[%d]", path, i), secret, errs)
+		}
+	case reflect.Map:
+		iter := val.MapRange()
+		for iter.Next() {
+			// map的值不可寻址，复制后解析再写回
+			elem := reflect.New(iter.Value().Type()).Elem()
+			elem.Set(iter.Value())
+			resolveValue(elem, joinPath(path, fmt.Sprint(iter.Key().Interface())), secret, errs)
+			val.SetMapIndex(iter.Key(), elem)
+		}
+	case reflect.Struct:
+		typ := val.Type()
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			fieldPath := path
+			if !field.Anonymous {
+				fieldPath = joinPath(path, fieldName(field))
+			}
+			resolveValue(val.Field(i), fieldPath, secret || field.Tag.Get(SecretTag) == "true", errs)
+		}
+	}
+}
+
+// 解析字符串中的占位符以及加密值，返回解析后的值以及是否发生解析
+// 加密值始终记录为密钥，占位符解析后的值仅在secret为true时记录，避免host、端口等普通配置被脱敏
+func resolveString(s string, secret bool) (string, bool, error) {
+	if encPattern.MatchString(strings.TrimSpace(s)) {
+		plaintext, err := DecryptSecret(s)
+		if err != nil {
+			return "", false, err
+		}
+		addSecret(plaintext)
+		return plaintext, true, nil
+	}
+	if !strings.Contains(s, "${") {
+		return s, false, nil
+	}
+	var errs []error
+	var resolved bool
+	result := secretPattern.ReplaceAllStringFunc(s, func(placeholder string) string {
+		match := secretPattern.FindStringSubmatch(placeholder)
+		resolver, ok := secretResolvers.Find(match[1])
+		if !ok || resolver == nil {
+			return placeholder
+		}
+		value, err := resolver.Resolve(match[2])
+		if err != nil {
+			errs = append(errs, errorx.Wrap(err, "resolve "+placeholder+" failed"))
+			return placeholder
+		}
+		if secret {
+			addSecret(value)
+		}
+		resolved = true
+		return value
+	})
+	if len(errs) > 0 {
+		return "", false, errors.Join(errs...)
+	}
+	return result, resolved, nil
+}
+
+// 读取环境变量
+func resolveEnv(name string) (string, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return "", errorx.Sprintf("env %s is not set", name)
+	}
+	return value, nil
+}
+
+// 读取文件内容，去除首尾空白
+func resolveFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", errorx.Wrap(err, "read secret file failed")
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// 根据 SecretKeyEnv 创建AES-GCM加密器
+func secretCipher() (cipher.AEAD, error) {
+	key := os.Getenv(SecretKeyEnv)
+	if key == "" {
+		return nil, errorx.Sprintf("env %s is not set", SecretKeyEnv)
+	}
+	// 优先按原始字节使用，长度不符时按base64解码
+	raw := []byte(key)
+	if !isAESKeySize(len(raw)) {
+		decoded, err := base64.StdEncoding.DecodeString(key)
+		if err != nil || !isAESKeySize(len(decoded)) {
+			return nil, errorx.Sprintf("env %s must be 16, 24 or 32 bytes, or its base64 encoding", SecretKeyEnv)
+		}
+		raw = decoded
+	}
+	block, err := aes.NewCipher(raw)
+	if err != nil {
+		return nil, errorx.Wrap(err, "create aes cipher failed")
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errorx.Wrap(err, "create gcm failed")
+	}
+	return gcm, nil
+}
+
+func isAESKeySize(n int) bool {
+	return n == 16 || n == 24 || n == 32
+}
+
+// 日志钩子，对日志内容以及字段中已解析的密钥脱敏
+type secretHook struct{}
+
+func (secretHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+func (secretHook) Fire(entry *log.Entry) error {
+	secretMu.RLock()
+	empty := len(secrets) == 0
+	secretMu.RUnlock()
+	if empty {
+		return nil
+	}
+	entry.Message = MaskSecret(entry.Message)
+	for key, value := range entry.Data {
+		switch v := value.(type) {
+		case string:
+			entry.Data[key] = MaskSecret(v)
+		case error:
+			if masked := MaskSecret(v.Error()); masked != v.Error() {
+				entry.Data[key] = errors.New(masked)
+			}
+		case fmt.Stringer:
+			if masked := MaskSecret(v.String()); masked != v.String() {
+				entry.Data[key] = masked
+			}
+		case map[string]any:
+			masked := make(map[string]any, len(v))
+			for k, item := range v {
+				if s, ok := item.(string); ok {
+					item = MaskSecret(s)
+				}
+				masked[k] = item
+			}
+			entry.Data[key] = masked
+		}
+	}
+	return nil
+}
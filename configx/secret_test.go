@@ -0,0 +1,109 @@
+package configx
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type secretTest struct {
+	Password string            `json:"password" secret:"true"`
+	Token    *string           `json:"token" secret:"true"`
+	Dsn      string            `json:"dsn" secret:"true"`
+	Host     string            `json:"host"`
+	Plain    string            `json:"plain"`
+	Extra    map[string]string `json:"extra"`
+	Items    []any             `json:"items"`
+}
+
+func TestResolveSecrets(t *testing.T) {
+	t.Setenv("TEST_DB_PASS", "env-secret")
+	t.Setenv("TEST_DB_HOST", "db.internal")
+	t.Setenv(SecretKeyEnv, "0123456789abcdef0123456789abcdef")
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte("file-secret\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := EncryptSecret("enc-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token := "${file:" + path + "}"
+	config := &secretTest{
+		Password: "${env:TEST_DB_PASS}",
+		Token:    &token,
+		Dsn:      "root:${env:TEST_DB_PASS}@tcp(localhost:3306)/demo",
+		Host:     "${env:TEST_DB_HOST}",
+		Plain:    "${unknown:keep}",
+		Extra:    map[string]string{"key": encrypted},
+		Items:    []any{"${env:TEST_DB_PASS}", 1},
+	}
+	if err = ResolveSecrets(config); err != nil {
+		t.Fatal(err)
+	}
+	if config.Password != "env-secret" || *config.Token != "file-secret" || config.Extra["key"] != "enc-secret" {
+		t.Fatalf("unexpected resolved config: %+v", config)
+	}
+	if config.Dsn != "root:env-secret@tcp(localhost:3306)/demo" || config.Plain != "${unknown:keep}" || config.Items[0] != "env-secret" {
+		t.Fatalf("unexpected resolved config: %+v", config)
+	}
+	if masked := MaskSecret(config.Dsn); strings.Contains(masked, "env-secret") || !strings.Contains(masked, MaskedSecret) {
+		t.Fatalf("secret is not masked: %s", masked)
+	}
+	if !IsSecret("enc-secret") || IsSecret("public") {
+		t.Fatal("unexpected secret check result")
+	}
+	// 未标记为敏感字段的占位符解析后不脱敏
+	if config.Host != "db.internal" || IsSecret("db.internal") {
+		t.Fatalf("non-secret field should not be masked: %s", config.Host)
+	}
+
+	// 安装钩子后日志脱敏
+	var buf bytes.Buffer
+	output := log.StandardLogger().Out
+	log.SetOutput(&buf)
+	defer log.SetOutput(output)
+	InstallSecretHook()
+	log.WithField("dsn", config.Dsn).Info("connect to " + config.Host)
+	if strings.Contains(buf.String(), "env-secret") || !strings.Contains(buf.String(), "db.internal") {
+		t.Fatalf("unexpected log output: %s", buf.String())
+	}
+
+	if err = ResolveSecrets(&secretTest{Password: "${env:TEST_NOT_EXIST}"}); err == nil || !strings.Contains(err.Error(), "password") {
+		t.Fatalf("expected resolve error with field path, got %v", err)
+	}
+}
+
+func TestMaskFields(t *testing.T) {
+	type embedded struct {
+		Key string `json:"key" secret:"true"`
+	}
+	config := struct {
+		embedded
+		Secrets []*secretTest `json:"secrets"`
+		Ignored string        `json:"-" secret:"true"`
+	}{
+		embedded: embedded{Key: "embedded-key"},
+		Secrets:  []*secretTest{{Password: "pass", Host: "mask.internal", Extra: map[string]string{"a": "b"}}},
+	}
+	masked, err := MaskFields(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := masked.(map[string]any)
+	item := result["secrets"].([]any)[0].(map[string]any)
+	if result["key"] != MaskedSecret || item["password"] != MaskedSecret || item["dsn"] != "" {
+		t.Errorf("tagged fields should be masked: %v", result)
+	}
+	if item["host"] != "mask.internal" || item["extra"].(map[string]any)["a"] != "b" {
+		t.Errorf("untagged fields should not be masked: %v", result)
+	}
+	if config.Secrets[0].Password != "pass" {
+		t.Error("original config should not be modified")
+	}
+}
@@ -0,0 +1,207 @@
+package configx
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ValidateTag 配置校验标签，多个规则以英文逗号分隔，例如：`validate:"required,min=1,max=65535"`
+// 支持的规则：
+// required：必填，值不能为零值
+// min=n/max=n：数值的最小/最大值，字符串、切片、map的最小/最大长度
+// oneof=a b c：枚举值，多个值以空格分隔
+// oneofci=a b c：枚举值，不区分大小写
+// url：合法的url，必须包含scheme以及host
+// hostport：合法的host:port，多个地址以英文逗号分隔
+// duration：合法的时间间隔，例如 10s、1m30s
+// 除required外，其他规则在值为零值时不做校验
+const ValidateTag = "validate"
+
+// FieldError 字段校验错误
+type FieldError struct {
+	Field    string `json:"field"`    // 字段路径，例如 "server.port"、"[0].host"
+	Rule     string `json:"rule"`     // 校验规则
+	Value    any    `json:"value"`    // 字段值
+	Message  string `json:"message"`  // 错误信息
+	Location string `json:"location"` // 字段来源
+}
+
+func (e *FieldError) Error() string {
+	if e.Location != "" {
+		return fmt.Sprintf("%s (%s): %s", e.Field, e.Location, e.Message)
+	}
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// ValidationError 配置校验错误，包含全部校验失败的字段
+type ValidationError struct {
+	Fields []*FieldError `json:"fields"`
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		messages = append(messages, field.Error())
+	}
+	return fmt.Sprintf("%d invalid config field(s): %s", len(e.Fields), strings.Join(messages, "; "))
+}
+
+// Validate 根据validate标签校验配置，校验失败时返回 *ValidationError
+func Validate(v any) error {
+	var fields []*FieldError
+	validateValue(reflect.ValueOf(v), "", &fields)
+	if len(fields) > 0 {
+		return &ValidationError{Fields: fields}
+	}
+	return nil
+}
+
+// 使用读取器读取后校验配置，字段来源优先使用读取器提供的字段来源，读取器为空时字段来源为self
+func validateWithReader(v any, reader Reader) []*FieldError {
+	var fields []*FieldError
+	validateValue(reflect.ValueOf(v), "", &fields)
+	var origins map[string]string
+	if or, ok := reader.(OriginReader); ok {
+		origins = or.Origins()
+	}
+	for _, field := range fields {
+		if origin, ok := origins[field.Field]; ok {
+			field.Location = origin
+		} else if reader != nil {
+			field.Location = reader.Location()
+		} else {
+			field.Location = "self"
+		}
+	}
+	return fields
+}
+
+// 递归校验
+func validateValue(val reflect.Value, path string, fields *[]*FieldError) {
+	switch val.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !val.IsNil() {
+			validateValue(val.Elem(), path, fields)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < val.Len(); i++ {
+			validateValue(val.Index(i), fmt.Sprintf("%s[%d]", path, i), fields)
+		}
+	case reflect.Struct:
+		typ := val.Type()
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			fieldPath := path
+			if !field.Anonymous {
+				fieldPath = joinPath(path, fieldName(field))
+			}
+			if tag := field.Tag.Get(ValidateTag); tag != "" && tag != "-" {
+				for _, rule := range strings.Split(tag, ",") {
+					if message := checkRule(val.Field(i), strings.TrimSpace(rule)); message != "" {
+						*fields = append(*fields, &FieldError{
+							Field:   fieldPath,
+							Rule:    rule,
+							Value:   val.Field(i).Interface(),
+							Message: message,
+						})
+					}
+				}
+			}
+			validateValue(val.Field(i), fieldPath, fields)
+		}
+	}
+}
+
+// 校验单个规则，校验通过时返回空字符串
+func checkRule(val reflect.Value, rule string) string {
+	name, param, _ := strings.Cut(rule, "=")
+	if name == "required" {
+		if val.IsZero() {
+			return "is required"
+		}
+		return ""
+	}
+	if val.IsZero() {
+		return ""
+	}
+	for val.Kind() == reflect.Pointer {
+		val = val.Elem()
+	}
+	switch name {
+	case "min", "max":
+		limit, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return fmt.Sprintf("invalid rule %q", rule)
+		}
+		size, isLength, ok := measure(val)
+		if !ok {
+			return ""
+		}
+		if name == "min" && size < limit {
+			if isLength {
+				return fmt.Sprintf("length must be at least %s", param)
+			}
+			return fmt.Sprintf("must be at least %s", param)
+		} else if name == "max" && size > limit {
+			if isLength {
+				return fmt.Sprintf("length must be at most %s", param)
+			}
+			return fmt.Sprintf("must be at most %s", param)
+		}
+	case "oneof", "oneofci":
+		value := fmt.Sprint(val.Interface())
+		options := strings.Fields(param)
+		for _, option := range options {
+			if option == value || (name == "oneofci" && strings.EqualFold(option, value)) {
+				return ""
+			}
+		}
+		return fmt.Sprintf("must be one of [%s]", strings.Join(options, " "))
+	case "url":
+		if u, err := url.Parse(fmt.Sprint(val.Interface())); err != nil || u.Scheme == "" || u.Host == "" {
+			return "must be a valid url"
+		}
+	case "hostport":
+		for _, addr := range strings.Split(fmt.Sprint(val.Interface()), ",") {
+			host, port, err := net.SplitHostPort(strings.TrimSpace(addr))
+			if err != nil || host == "" {
+				return "must be a valid host:port"
+			} else if n, err := strconv.Atoi(port); err != nil || n <= 0 || n > 65535 {
+				return "must be a valid host:port"
+			}
+		}
+	case "duration":
+		if val.Kind() == reflect.String {
+			if _, err := time.ParseDuration(val.String()); err != nil {
+				return "must be a valid duration, e.g. 10s, 1m30s"
+			}
+		}
+	default:
+		return fmt.Sprintf("unknown rule %q", rule)
+	}
+	return ""
+}
+
+// 获取数值或者长度，用于min/max校验
+func measure(val reflect.Value) (float64, bool, bool) {
+	switch val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(val.Int()), false, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(val.Uint()), false, true
+	case reflect.Float32, reflect.Float64:
+		return val.Float(), false, true
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return float64(val.Len()), true, true
+	default:
+		return 0, false, false
+	}
+}
@@ -1,16 +1,14 @@
 package dbx
 
 import (
+	"context"
+
 	"github.com/go-xuan/quanx/configx"
-	"github.com/go-xuan/typex"
-	"github.com/go-xuan/utilx/errorx"
 	"gorm.io/gorm"
 )
 
-var (
-	pool     = configx.NewPool[Client]()        // 客户端池
-	builders *typex.Enum[string, ClientBuilder] // 客户端构造函数池
-)
+// 客户端注册中心，默认使用gorm
+var registry = configx.NewRegistry[*Config, Client]("database", "gorm")
 
 func init() {
 	RegisterClientBuilder("gorm", GormClientBuilder) // 注册gorm客户端构建器
@@ -18,27 +16,16 @@ func init() {
 
 // RegisterClientBuilder 注册客户端构造函数
 func RegisterClientBuilder(name string, builder ClientBuilder) {
-	if builders == nil {
-		builders = typex.NewStringEnum[ClientBuilder]()
-	}
-	builders.Add(name, builder)
+	registry.Register(name, builder)
 }
 
-// NewClient 创建客户端
+// NewClient 创建客户端，驱动为空时使用gorm
 func NewClient(config *Config) (Client, error) {
-	if config.Driver == "" {
-		return GormClientBuilder(config)
-	}
-	if builders != nil {
-		if builder, ok := builders.Find(config.Driver); ok && builder != nil {
-			return builder(config)
-		}
-	}
-	return nil, errorx.Sprintf("client driver is not registered: %s", config.Driver)
+	return registry.Build(config.Driver, config)
 }
 
 // ClientBuilder 客户端构造函数
-type ClientBuilder func(*Config) (Client, error)
+type ClientBuilder = configx.Builder[*Config, Client]
 
 // Client 数据库客户端接口
 type Client interface {
@@ -46,34 +33,38 @@ type Client interface {
 	GetConfig() *Config // 获取配置
 	Close() error       // 关闭客户端, 释放资源
 
+	HealthCheck(ctx context.Context) error // 健康检查
+
 	Raw(sql string, dest any) error // 查询SQL, 将结果存储到dest中
 	Exec(sql string) error          // 执行SQL, 不返回结果
 }
 
+// DefaultPool 获取默认客户端池，与 Pool 不同的是，客户端池未初始化时不会panic
+func DefaultPool() *configx.Pool[Client] {
+	return registry.Pool()
+}
+
 // Pool 获取客户端池
 func Pool() *configx.Pool[Client] {
 	if !Initialized() {
-		panic("client pool not initialized")
+		panic("database client pool not initialized")
 	}
-	return pool
+	return registry.Pool()
 }
 
 // Initialized 是否初始化
 func Initialized() bool {
-	return pool.Initialized()
+	return registry.Pool().Initialized()
 }
 
 // AddClient 添加客户端
 func AddClient(source string, client Client) {
-	if client == nil {
-		return
-	}
-	pool.Add(source, client)
+	registry.Add(source, client)
 }
 
-// GetClient 获取客户端
+// GetClient 获取客户端，未指定 source 时返回 default，数据源不存在时panic
 func GetClient(source ...string) Client {
-	return pool.Get(source...)
+	return registry.MustGet(source...)
 }
 
 // GetConfig 获取配置
@@ -97,7 +88,5 @@ func GetGormDB(source ...string) *gorm.DB {
 
 // Close 关闭所有数据库客户端
 func Close() error {
-	return pool.Close(func(client Client) error {
-		return client.Close()
-	})
+	return registry.Close()
 }
@@ -1,6 +1,7 @@
 package dbx
 
 import (
+	"context"
 	"database/sql"
 	"time"
 
@@ -53,6 +54,17 @@ func (c *GormClient) Close() error {
 	return nil
 }
 
+func (c *GormClient) HealthCheck(ctx context.Context) error {
+	db, err := c.db.DB()
+	if err != nil {
+		return errorx.Wrap(err, "get sql db failed")
+	}
+	if err = db.PingContext(ctx); err != nil {
+		return errorx.Wrap(err, "ping database failed")
+	}
+	return nil
+}
+
 func (c *GormClient) Raw(sql string, dest interface{}) error {
 	raw := c.GetClient().Raw(sql)
 	if err := raw.Error; err != nil {
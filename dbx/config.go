@@ -3,6 +3,7 @@ package dbx
 import (
 	"fmt"
 	"net/url"
+	"reflect"
 	"strings"
 
 	"github.com/go-xuan/utilx/errorx"
@@ -21,70 +22,72 @@ const (
 
 // Config 数据库配置
 type Config struct {
-	Source        string            `json:"source" yaml:"source" default:"default"`           // 数据源名称
-	Driver        string            `json:"driver" yaml:"driver" default:"gorm"`              // 客户端驱动
-	Enable        bool              `json:"enable" yaml:"enable"`                             // 数据源启用
-	Dialect       string            `json:"dialect" yaml:"dialect"`                           // 数据库方言
-	Dsn           string            `json:"dsn" yaml:"dsn"`                                   // DSN连接字符串
-	Host          string            `json:"host" yaml:"host" default:"localhost"`             // 数据库Host
-	Port          int               `json:"port" yaml:"port"`                                 // 数据库端口
-	Username      string            `json:"username" yaml:"username"`                         // 用户名
-	Password      string            `json:"password" yaml:"password"`                         // 密码
-	Database      string            `json:"database" yaml:"database"`                         // 数据库名
-	Schema        string            `json:"schema" yaml:"schema"`                             // schema模式名
-	Options       map[string]string `json:"options" yaml:"options"`                           // 连接选项，可覆盖默认值
-	MaxOpenConns  int               `json:"maxOpenConns" yaml:"maxOpenConns" default:"100"`   // 最大打开连接
-	MaxIdleConns  int               `json:"maxIdleConns" yaml:"maxIdleConns" default:"10"`    // 最大空闲连接
-	MaxLifetime   int               `json:"maxLifetime" yaml:"maxLifetime" default:"10"`      // 连接存活时间(秒)
-	MaxIdleTime   int               `json:"maxIdleTime" yaml:"maxIdleTime" default:"10"`      // 连接空闲时间(毫秒)
-	LogLevel      string            `json:"logLevel" yaml:"logLevel" default:"warn"`          // 日志级别
-	SlowThreshold int               `json:"slowThreshold" yaml:"slowThreshold" default:"200"` // 慢查询阈值(毫秒)
-}
-
-// GetDSN 获取DSN
+	Source        string            `json:"source" yaml:"source" default:"default" comment:"数据源名称"`                       // 数据源名称
+	Driver        string            `json:"driver" yaml:"driver" default:"gorm" comment:"客户端驱动"`                          // 客户端驱动
+	Enable        bool              `json:"enable" yaml:"enable" comment:"数据源启用"`                                         // 数据源启用
+	Dialect       string            `json:"dialect" yaml:"dialect" validate:"oneof=mysql postgres pgsql" comment:"数据库方言"` // 数据库方言
+	Dsn           string            `json:"dsn" yaml:"dsn" secret:"true" comment:"DSN连接字符串"`                              // DSN连接字符串
+	Host          string            `json:"host" yaml:"host" default:"localhost" comment:"数据库Host"`                       // 数据库Host
+	Port          int               `json:"port" yaml:"port" validate:"max=65535" comment:"数据库端口"`                        // 数据库端口
+	Username      string            `json:"username" yaml:"username" comment:"用户名"`                                       // 用户名
+	Password      string            `json:"password" yaml:"password" secret:"true" comment:"密码"`                          // 密码
+	Database      string            `json:"database" yaml:"database" comment:"数据库名"`                                      // 数据库名
+	Schema        string            `json:"schema" yaml:"schema" comment:"schema模式名"`                                     // schema模式名
+	Options       map[string]string `json:"options" yaml:"options" comment:"连接选项，可覆盖默认值"`                                 // 连接选项，可覆盖默认值
+	MaxOpenConns  int               `json:"maxOpenConns" yaml:"maxOpenConns" default:"100" comment:"最大打开连接"`              // 最大打开连接
+	MaxIdleConns  int               `json:"maxIdleConns" yaml:"maxIdleConns" default:"10" comment:"最大空闲连接"`               // 最大空闲连接
+	MaxLifetime   int               `json:"maxLifetime" yaml:"maxLifetime" default:"10" comment:"连接存活时间(秒)"`              // 连接存活时间(秒)
+	MaxIdleTime   int               `json:"maxIdleTime" yaml:"maxIdleTime" default:"10" comment:"连接空闲时间(毫秒)"`             // 连接空闲时间(毫秒)
+	LogLevel      string            `json:"logLevel" yaml:"logLevel" default:"warn" comment:"日志级别"`                       // 日志级别
+	SlowThreshold int               `json:"slowThreshold" yaml:"slowThreshold" default:"200" comment:"慢查询阈值(毫秒)"`         // 慢查询阈值(毫秒)
+
+	pool *configx.Pool[Client] // 客户端所在的客户端池，用于配置热加载
+}
+
+// GetDSN 获取DSN，未配置时根据连接信息生成（不回写到配置，以便热加载时对比配置是否变化）
 func (c *Config) GetDSN() string {
-	if c.Dsn == "" {
-		dsn := strings.Builder{}
-		switch c.Dialect {
-		case MYSQL:
-			options := make(map[string]string)
-			options["clientFoundRows"] = "false"
-			options["timeout"] = "10s"
-			options["charset"] = "utf8"
-			options["collation"] = "utf8_general_ci"
-			options["parseTime"] = "true"
-			options["loc"] = "Asia/Shanghai"
-			for k, v := range c.Options {
-				options[k] = v
-			}
-			dsn.WriteString(fmt.Sprintf("%s:%s@tcp(%s:%d)/%s", c.Username, c.Password, c.Host, c.Port, c.Database))
-			if len(options) > 0 {
-				dsn.WriteString("?")
-				conn := ""
-				for k, v := range options {
-					dsn.WriteString(conn)
-					dsn.WriteString(k)
-					dsn.WriteString("=")
-					dsn.WriteString(url.QueryEscape(v))
-					conn = "&"
-				}
-			}
-		case POSTGRES, PGSQL:
-			dsn.WriteString(fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s",
-				c.Host, c.Port, c.Username, c.Password, c.Database))
-			options := make(map[string]string)
-			options["sslmode"] = "disable"
-			options["TimeZone"] = "Asia/Shanghai"
-			for k, v := range c.Options {
-				options[k] = v
-			}
+	if c.Dsn != "" {
+		return c.Dsn
+	}
+	dsn := strings.Builder{}
+	switch c.Dialect {
+	case MYSQL:
+		options := make(map[string]string)
+		options["clientFoundRows"] = "false"
+		options["timeout"] = "10s"
+		options["charset"] = "utf8"
+		options["collation"] = "utf8_general_ci"
+		options["parseTime"] = "true"
+		options["loc"] = "Asia/Shanghai"
+		for k, v := range c.Options {
+			options[k] = v
+		}
+		dsn.WriteString(fmt.Sprintf("%s:%s@tcp(%s:%d)/%s", c.Username, c.Password, c.Host, c.Port, c.Database))
+		if len(options) > 0 {
+			dsn.WriteString("?")
+			conn := ""
 			for k, v := range options {
-				dsn.WriteString(fmt.Sprintf(" %s=%s", k, v))
+				dsn.WriteString(conn)
+				dsn.WriteString(k)
+				dsn.WriteString("=")
+				dsn.WriteString(url.QueryEscape(v))
+				conn = "&"
 			}
 		}
-		c.Dsn = dsn.String()
+	case POSTGRES, PGSQL:
+		dsn.WriteString(fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s",
+			c.Host, c.Port, c.Username, c.Password, c.Database))
+		options := make(map[string]string)
+		options["sslmode"] = "disable"
+		options["TimeZone"] = "Asia/Shanghai"
+		for k, v := range c.Options {
+			options[k] = v
+		}
+		for k, v := range options {
+			dsn.WriteString(fmt.Sprintf(" %s=%s", k, v))
+		}
 	}
-	return c.Dsn
+	return dsn.String()
 }
 
 // Copy 复制配置
@@ -109,6 +112,13 @@ func (c *Config) Copy() *Config {
 	}
 }
 
+// 配置是否相同，忽略客户端池
+func (c *Config) equal(other *Config) bool {
+	a, b := *c, *other
+	a.pool, b.pool = nil, nil
+	return reflect.DeepEqual(a, b)
+}
+
 // LogFields 日志字段
 func (c *Config) LogFields() map[string]interface{} {
 	fields := make(map[string]interface{})
@@ -129,10 +139,17 @@ func (c *Config) Readers() []configx.Reader {
 	return []configx.Reader{
 		nacosx.NewReader("database.yaml"),
 		configx.NewFileReader("database.yaml"),
+		configx.NewEnvReader(configx.EnvPrefix + "_DATABASE"),
 	}
 }
 
 func (c *Config) Execute() error {
+	return c.ExecuteWith(registry.Pool())
+}
+
+// ExecuteWith 创建客户端并添加到指定的客户端池
+func (c *Config) ExecuteWith(p *configx.Pool[Client]) error {
+	c.pool = p
 	if c.Enable {
 		logger_ := log.WithFields(c.LogFields())
 		client, err := NewClient(c)
@@ -140,12 +157,21 @@ func (c *Config) Execute() error {
 			logger_.WithError(err).Error("create database client failed")
 			return errorx.Wrap(err, "create database client failed")
 		}
-		AddClient(c.Source, client)
+		p.Add(c.Source, client)
 		logger_.Info("init database success")
 	}
 	return nil
 }
 
+// OnChange 数据库配置变更，重建发生变化的数据源客户端，并以相同的数据源名称替换客户端池中的客户端
+func (c *Config) OnChange(_, updated configx.Configurator) error {
+	config, ok := updated.(*Config)
+	if !ok {
+		return errorx.New("unexpected database config type")
+	}
+	return reloadConfigs(c.pool, Configs{c}, Configs{config})
+}
+
 type Configs []*Config
 
 func (s Configs) Valid() bool {
@@ -156,19 +182,94 @@ func (s Configs) Readers() []configx.Reader {
 	return []configx.Reader{
 		nacosx.NewReader("database.yaml"),
 		configx.NewFileReader("database.yaml"),
+		configx.NewEnvReader(configx.EnvPrefix + "_DATABASE"),
 	}
 }
 
 func (s Configs) Execute() error {
+	return s.ExecuteWith(registry.Pool())
+}
+
+// ExecuteWith 创建客户端并添加到指定的客户端池
+func (s Configs) ExecuteWith(p *configx.Pool[Client]) error {
 	for _, config := range s {
-		if err := config.Execute(); err != nil {
+		if err := config.ExecuteWith(p); err != nil {
 			return errorx.Wrap(err, "execute database config failed")
 		}
 	}
-	if !Initialized() {
+	if !p.Initialized() {
 		err := errorx.New("no enabled database source")
 		log.WithField("error", err.Error()).Warn("init database failed")
 		return err
 	}
 	return nil
 }
+
+// OnChange 数据库配置变更，重建发生变化的数据源客户端，并以相同的数据源名称替换客户端池中的客户端
+func (s Configs) OnChange(_, updated configx.Configurator) error {
+	var configs Configs
+	switch v := updated.(type) {
+	case *Configs:
+		configs = *v
+	case Configs:
+		configs = v
+	default:
+		return errorx.New("unexpected database configs type")
+	}
+	var p *configx.Pool[Client]
+	if len(s) > 0 {
+		p = s[0].pool
+	}
+	return reloadConfigs(p, s, configs)
+}
+
+// 对比变更前后的配置，重建发生变化的数据源客户端，移除已删除或者已禁用的数据源
+// 新客户端创建成功后才会替换旧客户端，旧客户端在 configx.Pool 的延迟关闭时间后关闭
+func reloadConfigs(p *configx.Pool[Client], olds, news Configs) error {
+	if p == nil {
+		p = registry.Pool()
+	}
+	index := make(map[string]*Config, len(olds))
+	for _, config := range olds {
+		index[config.Source] = config
+	}
+	remain := make(map[string]bool, len(news))
+	for _, config := range news {
+		config.pool = p
+		remain[config.Source] = true
+		logger := log.WithFields(config.LogFields())
+		old, exist := index[config.Source]
+		if exist && old.equal(config) {
+			continue
+		}
+		if !config.Enable {
+			if exist && old.Enable {
+				removeSource(p, config)
+			}
+			continue
+		}
+		client, err := NewClient(config)
+		if err != nil {
+			logger.WithError(err).Error("rebuild database client failed")
+			return errorx.Wrap(err, "rebuild database client failed")
+		}
+		p.Replace(config.Source, client)
+		logger.Info("reload database success")
+	}
+	for _, config := range olds {
+		if !remain[config.Source] && config.Enable {
+			removeSource(p, config)
+		}
+	}
+	return nil
+}
+
+// 移除数据源，旧客户端在 configx.Pool 的延迟关闭时间后关闭
+func removeSource(p *configx.Pool[Client], config *Config) {
+	logger := log.WithFields(config.LogFields())
+	if err := p.Remove(config.Source); err != nil {
+		logger.WithError(err).Warn("remove database source failed")
+		return
+	}
+	logger.Info("remove database source success")
+}
@@ -1,6 +1,8 @@
 package dbx
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/go-xuan/quanx/configx"
@@ -11,3 +13,89 @@ func TestDatabase(t *testing.T) {
 		panic(err)
 	}
 }
+
+type reloadTestClient struct {
+	Client
+	config *Config
+	closed bool
+}
+
+func (c *reloadTestClient) GetConfig() *Config { return c.config }
+
+func (c *reloadTestClient) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestReloadConfigs(t *testing.T) {
+	var builds int
+	RegisterClientBuilder("reload-test", func(config *Config) (Client, error) {
+		builds++
+		config.GetDSN()
+		return &reloadTestClient{config: config}, nil
+	})
+	dir := t.TempDir()
+	data := `[{"source":"default","driver":"reload-test","enable":true,"dialect":"mysql","host":"localhost","port":3306}]`
+	_ = os.WriteFile(filepath.Join(dir, "database.json"), []byte(data), 0644)
+	read := func() Configs {
+		var configs Configs
+		if err := (&configx.FileReader{Dir: dir, Name: "database.json"}).Read(&configs); err != nil {
+			t.Fatal(err)
+		}
+		return configs
+	}
+
+	pool := configx.NewPool[Client]()
+	pool.SetRetireDelay(0)
+	olds := read()
+	if err := olds.ExecuteWith(pool); err != nil {
+		t.Fatal(err)
+	}
+	client, _ := pool.Find("default")
+
+	// 配置未变化时不重建客户端
+	if err := olds.OnChange(olds, read()); err != nil {
+		t.Fatal(err)
+	}
+	if current, _ := pool.Find("default"); builds != 1 || current != client {
+		t.Fatalf("expected client not replaced, builds=%d", builds)
+	}
+
+	// 配置变化时重建客户端
+	updated := read()
+	updated[0].Port = 3307
+	if err := olds.OnChange(olds, updated); err != nil {
+		t.Fatal(err)
+	}
+	if current, _ := pool.Find("default"); builds != 2 || current == client || !client.(*reloadTestClient).closed {
+		t.Fatalf("expected client replaced, builds=%d", builds)
+	}
+
+	// 新增数据源后再删除以及禁用数据源时移除客户端
+	olds = updated
+	added := append(read(), &Config{Source: "slave", Driver: "reload-test", Enable: true, Dialect: "mysql", Host: "localhost", Port: 3308})
+	added[0].Port = 3307
+	if err := olds.OnChange(olds, added); err != nil {
+		t.Fatal(err)
+	}
+	slave, ok := pool.Find("slave")
+	if !ok || builds != 3 {
+		t.Fatalf("expected slave source added, builds=%d", builds)
+	}
+	removed := read()
+	removed[0].Port = 3307
+	if err := added.OnChange(added, removed); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok = pool.Find("slave"); ok || !slave.(*reloadTestClient).closed {
+		t.Fatal("expected deleted source removed from pool")
+	}
+	disabled := read()
+	disabled[0].Port, disabled[0].Enable = 3307, false
+	if err := removed.OnChange(removed, disabled); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok = pool.Find("default"); ok {
+		t.Fatal("expected disabled source removed from pool")
+	}
+}
@@ -9,8 +9,13 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
-// NewClient 创建客户端
+// NewClient 创建客户端，驱动为空时使用elastic驱动
 func NewClient(config *Config) (*Client, error) {
+	return registry.Build(config.Driver, config)
+}
+
+// ElasticClientBuilder elastic客户端构造函数
+func ElasticClientBuilder(config *Config) (*Client, error) {
 	client, err := NewEsClient(config)
 	if err != nil {
 		return nil, errorx.Wrap(err, "create elastic-search client failed")
@@ -61,6 +66,15 @@ func (c *Client) Close() error {
 	return nil
 }
 
+func (c *Client) HealthCheck(ctx context.Context) error {
+	if _, code, err := c.client.Ping(c.config.Url).Do(ctx); err != nil {
+		return errorx.Wrap(err, "ping elastic-search failed")
+	} else if code != 200 {
+		return errorx.Sprintf("ping elastic-search failed, status code: %d", code)
+	}
+	return nil
+}
+
 // CreateIndex 创建索引
 func (c *Client) CreateIndex(ctx context.Context, index string) (bool, error) {
 	res, err := c.client.CreateIndex(index).Do(ctx)
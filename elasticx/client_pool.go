@@ -5,30 +5,39 @@ import (
 	"github.com/olivere/elastic/v7"
 )
 
-// 客户端池
-var pool = configx.NewPool[*Client]()
+// 客户端注册中心，默认使用elastic驱动
+var registry = configx.NewRegistry[*Config, *Client]("elastic", "elastic")
+
+func init() {
+	RegisterClientBuilder("elastic", ElasticClientBuilder) // 注册elastic客户端构建器
+}
+
+// RegisterClientBuilder 注册客户端构造函数
+func RegisterClientBuilder(name string, builder ClientBuilder) {
+	registry.Register(name, builder)
+}
+
+// ClientBuilder 客户端构造函数
+type ClientBuilder = configx.Builder[*Config, *Client]
 
 // Pool 获取客户端池
 func Pool() *configx.Pool[*Client] {
-	return pool
+	return registry.Pool()
 }
 
 // Initialized 是否初始化
 func Initialized() bool {
-	return pool.Initialized()
+	return registry.Pool().Initialized()
 }
 
 // AddClient 添加客户端
 func AddClient(source string, client *Client) {
-	if client == nil {
-		return
-	}
-	pool.Add(source, client)
+	registry.Add(source, client)
 }
 
-// GetClient 获取客户端
+// GetClient 获取客户端，未指定 source 时返回 default，数据源不存在时panic
 func GetClient(source ...string) *Client {
-	return pool.Get(source...)
+	return registry.MustGet(source...)
 }
 
 // GetConfig 获取配置
@@ -40,3 +49,8 @@ func GetConfig(source ...string) *Config {
 func GetInstance(source ...string) *elastic.Client {
 	return GetClient(source...).GetClient()
 }
+
+// Close 关闭所有客户端
+func Close() error {
+	return registry.Close()
+}
@@ -12,18 +12,20 @@ import (
 
 // Config ES配置
 type Config struct {
-	Source   string   `json:"source" yaml:"source" default:"default"` // 数据源名称
-	Enable   bool     `json:"enable" yaml:"enable"`                   // 数据源启用
-	Url      string   `json:"url" yaml:"url"`                         // 地址
-	Username string   `json:"username" yaml:"username"`               // 用户名
-	Password string   `json:"password" yaml:"password"`               // 密码
-	Indices  []string `json:"indices" yaml:"indices"`                 // 索引
+	Source   string   `json:"source" yaml:"source" default:"default" comment:"数据源名称"` // 数据源名称
+	Driver   string   `json:"driver" yaml:"driver" default:"elastic" comment:"客户端驱动"` // 客户端驱动
+	Enable   bool     `json:"enable" yaml:"enable" comment:"数据源启用"`                   // 数据源启用
+	Url      string   `json:"url" yaml:"url" validate:"url" comment:"地址"`             // 地址
+	Username string   `json:"username" yaml:"username" comment:"用户名"`                 // 用户名
+	Password string   `json:"password" yaml:"password" secret:"true" comment:"密码"`    // 密码
+	Indices  []string `json:"indices" yaml:"indices" comment:"索引"`                    // 索引
 }
 
 // LogFields 日志字段
 func (c *Config) LogFields() map[string]interface{} {
 	fields := make(map[string]interface{})
 	fields["source"] = c.Source
+	fields["builder"] = c.Driver
 	fields["address"] = c.Url
 	return fields
 }
@@ -36,10 +38,16 @@ func (c *Config) Readers() []configx.Reader {
 	return []configx.Reader{
 		nacosx.NewReader("elastic.yaml"),
 		configx.NewFileReader("elastic.yaml"),
+		configx.NewEnvReader(configx.EnvPrefix + "_ELASTIC"),
 	}
 }
 
 func (c *Config) Execute() error {
+	return c.ExecuteWith(registry.Pool())
+}
+
+// ExecuteWith 创建客户端并添加到指定的客户端池
+func (c *Config) ExecuteWith(p *configx.Pool[*Client]) error {
 	if c.Enable {
 		logger := log.WithFields(c.LogFields())
 		client, err := NewClient(c)
@@ -54,7 +62,7 @@ func (c *Config) Execute() error {
 				return errorx.Wrap(err, "create index failed")
 			}
 		}
-		AddClient(c.Source, client)
+		p.Add(c.Source, client)
 		logger.Info("init elastic search success")
 	}
 	return nil
@@ -70,16 +78,22 @@ func (s Configs) Readers() []configx.Reader {
 	return []configx.Reader{
 		nacosx.NewReader("elastic.yaml"),
 		configx.NewFileReader("elastic.yaml"),
+		configx.NewEnvReader(configx.EnvPrefix + "_ELASTIC"),
 	}
 }
 
 func (s Configs) Execute() error {
+	return s.ExecuteWith(registry.Pool())
+}
+
+// ExecuteWith 创建客户端并添加到指定的客户端池
+func (s Configs) ExecuteWith(p *configx.Pool[*Client]) error {
 	for _, config := range s {
-		if err := config.Execute(); err != nil {
+		if err := config.ExecuteWith(p); err != nil {
 			return errorx.Wrap(err, "execute elastic config failed")
 		}
 	}
-	if !Initialized() {
+	if !p.Initialized() {
 		err := errorx.New("no enabled elastic search source")
 		log.WithField("error", err.Error()).Warn("init elastic search failed")
 		return err
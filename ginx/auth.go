@@ -38,10 +38,14 @@ func AuthValidate() AuthValidator {
 	return authValidator
 }
 
-// GetAuthCache 获取auth缓存
+// GetAuthCache 获取auth缓存，未配置auth数据源时使用默认缓存客户端
 func GetAuthCache() AuthCache {
 	if authCache == nil {
-		authCache = cachex.GetClient("auth")
+		if client, ok := cachex.DefaultPool().Find("auth"); ok {
+			authCache = client
+		} else {
+			authCache = cachex.GetClient()
+		}
 	}
 	return authCache
 }
@@ -16,7 +16,7 @@ import (
 
 // JwtConfig JWT鉴权配置，实现 configx.Configurator 接口用于自动加载
 type JwtConfig struct {
-	Secret string         `json:"secret" yaml:"secret"` // JWT密钥
+	Secret string         `json:"secret" yaml:"secret" secret:"true"` // JWT密钥
 	White  map[string]string `json:"white" yaml:"white"` // 鉴权白名单，map[URL路径]HTTP方法，*表示支持所有方法
 	Cache  *cachex.Config `json:"cache" yaml:"cache"`   // 缓存客户端配置
 }
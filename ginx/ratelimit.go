@@ -0,0 +1,262 @@
+package ginx
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-xuan/typex"
+	"github.com/go-xuan/utilx/errorx"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/go-xuan/quanx/cachex"
+	"github.com/go-xuan/quanx/configx"
+	"github.com/go-xuan/quanx/nacosx"
+)
+
+// 限流key提取方式
+const (
+	RateLimitByIP     = "ip"     // 按照客户端IP限流
+	RateLimitByUser   = "user"   // 按照登录用户限流，未登录时不限流
+	RateLimitByGlobal = "global" // 全局限流
+
+	TokenBucket   = "token_bucket"   // 令牌桶
+	SlidingWindow = "sliding_window" // 滑动窗口
+
+	rateLimitKeyPrefix = "ratelimit:"
+)
+
+// RateLimitKeyFunc 限流key提取函数，返回空字符串时不限流
+type RateLimitKeyFunc func(ctx *gin.Context) string
+
+// 限流key提取函数注册表
+var rateLimitKeys = typex.NewEnum[string, RateLimitKeyFunc]()
+
+// 配置文件中的限流规则
+var rateLimitRules atomic.Pointer[[]*RateLimitRule]
+
+func init() {
+	rateLimitKeys.Add(RateLimitByIP, GetClientIP).
+		Add(RateLimitByUser, func(ctx *gin.Context) string {
+			if user := GetSessionUser(ctx); user != nil {
+				return user.GetUserId().String()
+			}
+			return ""
+		}).
+		Add(RateLimitByGlobal, func(ctx *gin.Context) string {
+			return RateLimitByGlobal
+		})
+}
+
+// RegisterRateLimitKey 注册自定义限流key提取函数，可在限流规则的key中引用
+func RegisterRateLimitKey(name string, fn RateLimitKeyFunc) {
+	if name != "" && fn != nil {
+		rateLimitKeys.Add(name, fn)
+	}
+}
+
+// RateLimit 限流中间件，name用于区分不同限流器的key，超出限制时返回429并设置Retry-After，限流器异常时不限流
+func RateLimit(name string, limiter cachex.RateLimiter, keyFunc RateLimitKeyFunc) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if allowRequest(ctx, name, limiter, keyFunc) {
+			ctx.Next()
+		}
+	}
+}
+
+// RateLimitByConfig 按照限流配置（ratelimit.yaml）限流的中间件，依次校验匹配当前路由的全部规则
+func RateLimitByConfig(ctx *gin.Context) {
+	if rules := rateLimitRules.Load(); rules != nil {
+		path := ctx.FullPath()
+		if path == "" {
+			path = ctx.Request.URL.Path
+		}
+		for _, rule := range *rules {
+			if !rule.Match(ctx.Request.Method, path) {
+				continue
+			}
+			limiter, err := rule.NewLimiter()
+			if err != nil {
+				GetLogger(ctx).WithField("rule", rule.Name).WithError(err).Warn("create rate limiter failed")
+				continue
+			}
+			keyFunc, _ := rateLimitKeys.Find(rule.Key)
+			if !allowRequest(ctx, rule.Name, limiter, keyFunc) {
+				return
+			}
+		}
+	}
+	ctx.Next()
+}
+
+// 校验请求是否允许通过，拒绝时响应429
+func allowRequest(ctx *gin.Context, name string, limiter cachex.RateLimiter, keyFunc RateLimitKeyFunc) bool {
+	if keyFunc == nil {
+		return true
+	}
+	key := keyFunc(ctx)
+	if key == "" {
+		return true
+	}
+	result, err := limiter.Allow(ctx, rateLimitKeyPrefix+name+":"+key)
+	if err != nil {
+		GetLogger(ctx).WithField("limiter", name).WithError(err).Warn("rate limit failed")
+		return true
+	}
+	header := ctx.Writer.Header()
+	header.Set("X-RateLimit-Limit", strconv.FormatInt(result.Limit, 10))
+	header.Set("X-RateLimit-Remaining", strconv.FormatInt(result.Remaining, 10))
+	if !result.Allowed {
+		header.Set("Retry-After", strconv.FormatInt(retryAfterSeconds(result.RetryAfter), 10))
+		CustomResponse(ctx, NewResponse(RateLimitCode, nil))
+		return false
+	}
+	return true
+}
+
+// Retry-After以秒为单位，向上取整且至少为1
+func retryAfterSeconds(d time.Duration) int64 {
+	return max(int64(math.Ceil(d.Seconds())), 1)
+}
+
+// GetRateLimitRules 获取限流配置中的规则
+func GetRateLimitRules() []*RateLimitRule {
+	if rules := rateLimitRules.Load(); rules != nil {
+		return *rules
+	}
+	return nil
+}
+
+// RateLimitConfig 限流配置
+type RateLimitConfig struct {
+	Rules []*RateLimitRule `json:"rules" yaml:"rules"` // 限流规则
+}
+
+// RateLimitRule 限流规则
+type RateLimitRule struct {
+	Name      string  `json:"name" yaml:"name"`           // 规则名称，作为限流key的一部分
+	Source    string  `json:"source" yaml:"source"`       // 缓存数据源，默认为default
+	Path      string  `json:"path" yaml:"path"`           // 路由（例如/user/:id），以*结尾时按前缀匹配，为空时匹配全部
+	Method    string  `json:"method" yaml:"method"`       // 请求方法，为空时匹配全部
+	Key       string  `json:"key" yaml:"key"`             // 限流key提取方式：ip、user、global或者自定义，默认ip
+	Algorithm string  `json:"algorithm" yaml:"algorithm"` // 限流算法：token_bucket、sliding_window，默认token_bucket
+	Rate      float64 `json:"rate" yaml:"rate"`           // 令牌桶每秒生成的令牌数
+	Burst     int64   `json:"burst" yaml:"burst"`         // 令牌桶容量，默认与rate相同
+	Limit     int64   `json:"limit" yaml:"limit"`         // 滑动窗口内的最大请求数
+	Window    int     `json:"window" yaml:"window"`       // 滑动窗口时长（秒）
+}
+
+// Match 是否匹配请求
+func (r *RateLimitRule) Match(method, path string) bool {
+	if r.Method != "" && !strings.EqualFold(r.Method, method) {
+		return false
+	}
+	if prefix, ok := strings.CutSuffix(r.Path, "*"); ok {
+		return strings.HasPrefix(path, prefix)
+	}
+	return r.Path == "" || r.Path == path
+}
+
+// NewLimiter 创建限流器，每次调用时获取缓存客户端，因此缓存配置变更后立即生效
+func (r *RateLimitRule) NewLimiter() (cachex.RateLimiter, error) {
+	var source []string
+	if r.Source != "" {
+		source = append(source, r.Source)
+	}
+	client, err := cachex.DefaultPool().Get(source...)
+	if err != nil {
+		return nil, errorx.Wrap(err, "get cache client failed")
+	}
+	if r.Algorithm == SlidingWindow {
+		return cachex.NewSlidingWindowLimiter(client, r.Limit, time.Duration(r.Window)*time.Second)
+	}
+	return cachex.NewTokenBucketLimiter(client, r.Rate, r.Burst)
+}
+
+// 填充默认值并校验规则
+func (r *RateLimitRule) valid() bool {
+	if r.Key == "" {
+		r.Key = RateLimitByIP
+	}
+	if r.Algorithm == "" {
+		r.Algorithm = TokenBucket
+	}
+	if r.Burst <= 0 {
+		r.Burst = int64(math.Ceil(r.Rate))
+	}
+	if r.Name == "" {
+		return false
+	}
+	switch r.Algorithm {
+	case TokenBucket:
+		return r.Rate > 0
+	case SlidingWindow:
+		return r.Limit > 0 && r.Window > 0
+	}
+	return false
+}
+
+func (c *RateLimitConfig) LogFields() map[string]interface{} {
+	names := make([]string, 0, len(c.Rules))
+	for _, rule := range c.Rules {
+		names = append(names, rule.Name)
+	}
+	fields := make(map[string]interface{})
+	fields["rules"] = strings.Join(names, ",")
+	return fields
+}
+
+func (c *RateLimitConfig) Valid() bool {
+	if len(c.Rules) == 0 {
+		return false
+	}
+	for _, rule := range c.Rules {
+		if rule == nil || !rule.valid() {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *RateLimitConfig) Readers() []configx.Reader {
+	return []configx.Reader{
+		nacosx.NewReader("ratelimit.yaml"),
+		configx.NewFileReader("ratelimit.yaml"),
+	}
+}
+
+func (c *RateLimitConfig) Execute() error {
+	if err := c.check(); err != nil {
+		return errorx.Wrap(err, "init rate limit failed")
+	}
+	rateLimitRules.Store(&c.Rules)
+	log.WithFields(c.LogFields()).Info("init rate limit success")
+	return nil
+}
+
+// OnChange 限流规则变更，实时生效
+func (c *RateLimitConfig) OnChange(old, updated configx.Configurator) error {
+	config, ok := updated.(*RateLimitConfig)
+	if !ok {
+		return errorx.New("unexpected rate limit config type")
+	}
+	if err := config.check(); err != nil {
+		return errorx.Wrap(err, "reload rate limit failed")
+	}
+	rateLimitRules.Store(&config.Rules)
+	log.WithFields(config.LogFields()).Info("reload rate limit success")
+	return nil
+}
+
+// 校验规则引用的限流key提取方式均已注册
+func (c *RateLimitConfig) check() error {
+	for _, rule := range c.Rules {
+		if _, ok := rateLimitKeys.Find(rule.Key); !ok {
+			return errorx.Sprintf("rate limit key not registered: %s", rule.Key)
+		}
+	}
+	return nil
+}
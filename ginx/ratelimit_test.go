@@ -0,0 +1,105 @@
+package ginx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-xuan/quanx/cachex"
+)
+
+func TestRateLimit(t *testing.T) {
+	client, err := cachex.NewClient(&cachex.Config{Source: "ratelimit", Driver: "local", Marshal: "json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	limiter, err := cachex.NewTokenBucketLimiter(client, 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	engine := DefaultEngine()
+	engine.GET("/ping", RateLimit("ping", limiter, GetClientIP), func(ctx *gin.Context) {
+		Success(ctx, "pong")
+	})
+	for i, want := range []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests} {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ping", nil))
+		if w.Code != want {
+			t.Fatalf("request %d: expected status %d, got %d", i, want, w.Code)
+		}
+		if want == http.StatusTooManyRequests && w.Header().Get("Retry-After") != "1" {
+			t.Fatalf("unexpected Retry-After: %q", w.Header().Get("Retry-After"))
+		}
+	}
+}
+
+func TestRateLimitByConfig(t *testing.T) {
+	client, err := cachex.NewClient(&cachex.Config{Source: "ratelimit", Driver: "local", Marshal: "json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cachex.AddClient("ratelimit", client)
+	defer cachex.DefaultPool().Remove("ratelimit")
+
+	RegisterRateLimitKey("header", func(ctx *gin.Context) string {
+		return ctx.GetHeader("X-Tenant")
+	})
+	config := &RateLimitConfig{Rules: []*RateLimitRule{
+		{Name: "tenant", Source: "ratelimit", Path: "/api/*", Key: "header", Algorithm: SlidingWindow, Limit: 1, Window: 60},
+	}}
+	if !config.Valid() {
+		t.Fatal("expected valid config")
+	}
+	if err = config.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	defer rateLimitRules.Store(nil)
+
+	engine := DefaultEngine()
+	engine.Use(RateLimitByConfig)
+	engine.GET("/api/user/:id", func(ctx *gin.Context) { Success(ctx, nil) })
+	engine.GET("/health", func(ctx *gin.Context) { Success(ctx, nil) })
+	serve := func(path, tenant string) int {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		if tenant != "" {
+			r.Header.Set("X-Tenant", tenant)
+		}
+		engine.ServeHTTP(w, r)
+		return w.Code
+	}
+	for _, c := range []struct {
+		path, tenant string
+		want         int
+	}{
+		{"/api/user/1", "a", http.StatusOK},
+		{"/api/user/2", "a", http.StatusTooManyRequests},
+		{"/api/user/1", "b", http.StatusOK},
+		{"/api/user/1", "", http.StatusOK}, // 未提取到key时不限流
+		{"/health", "a", http.StatusOK},    // 未匹配规则
+	} {
+		if got := serve(c.path, c.tenant); got != c.want {
+			t.Fatalf("%s tenant=%q: expected status %d, got %d", c.path, c.tenant, c.want, got)
+		}
+	}
+
+	// 热加载后使用新的规则
+	updated := &RateLimitConfig{Rules: []*RateLimitRule{{Name: "tenant-v2", Source: "ratelimit", Key: "header", Rate: 100}}}
+	if !updated.Valid() {
+		t.Fatal("expected valid config")
+	}
+	if err = config.OnChange(config, updated); err != nil {
+		t.Fatal(err)
+	}
+	if got := serve("/api/user/2", "a"); got != http.StatusOK {
+		t.Fatalf("expected status 200 after reload, got %d", got)
+	}
+	invalid := &RateLimitConfig{Rules: []*RateLimitRule{{Name: "bad", Key: "unknown", Rate: 1}}}
+	if !invalid.Valid() || config.OnChange(config, invalid) == nil {
+		t.Fatal("expected error for unregistered key")
+	}
+}
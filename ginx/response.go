@@ -11,6 +11,7 @@ const (
 	SuccessCode      = 10000 // 请求成功
 	FailedCode       = 99999 // 请求失败
 	AuthFailedCode   = 10401 // 鉴权失败
+	RateLimitCode    = 10429 // 请求过于频繁
 	ParamErrorCode   = 10501 // 请求参数错误
 	RequiredCode     = 10502 // 请求参数必填
 	UploadFailedCode = 10601 // 上传失败
@@ -24,6 +25,7 @@ func init() {
 	CodeEnum.Add(SuccessCode, "success").
 		Add(FailedCode, "failed").
 		Add(AuthFailedCode, "auth failed").
+		Add(RateLimitCode, "too many requests").
 		Add(ParamErrorCode, "parse param failed").
 		Add(RequiredCode, "param required").
 		Add(UploadFailedCode, "upload failed").
@@ -61,6 +63,8 @@ func CustomResponse(ctx *gin.Context, response *Response) {
 		code = http.StatusOK
 	case AuthFailedCode:
 		code = http.StatusForbidden
+	case RateLimitCode:
+		code = http.StatusTooManyRequests
 	case ParamErrorCode:
 		code = http.StatusBadRequest
 	default:
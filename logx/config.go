@@ -2,7 +2,8 @@ package logx
 
 import (
 	"io"
-	"strings"
+	"reflect"
+	"sync/atomic"
 
 	"github.com/go-xuan/utilx/errorx"
 	"github.com/go-xuan/utilx/osx"
@@ -17,6 +18,7 @@ const (
 	LevelTrace = "trace"
 	LevelDebug = "debug"
 	LevelInfo  = "info"
+	LevelWarn  = "warn"
 	LevelError = "error"
 	LevelFatal = "fatal"
 	LevelPanic = "panic"
@@ -29,7 +31,7 @@ const (
 	TimeLayout    = "2006-01-02 15:04:05.000" // 时间格式化
 )
 
-var _config *Config // 日志配置
+var _config atomic.Pointer[Config] // 日志配置
 
 func init() {
 	// 设置默认日志输出
@@ -40,18 +42,18 @@ func init() {
 
 // GetConfig 获取日志配置
 func GetConfig() *Config {
-	return _config
+	return _config.Load()
 }
 
 // Config 日志配置
 type Config struct {
-	Name      string       `json:"name" yaml:"name" default:"app"`            // 日志文件名
-	Level     string       `json:"level" yaml:"level" default:"info"`         // 默认日志级别
-	Formatter string       `json:"formatter" yaml:"formatter" default:"json"` // 默认日志格式
-	Writer    string       `json:"writer" yaml:"writer" default:"console"`    // 默认日志输出
-	Color     bool         `json:"color" yaml:"color" default:"false"`        // 使用颜色
-	Caller    bool         `json:"caller" yaml:"caller" default:"false"`      // caller开关
-	Hooks     []HookConfig `json:"hooks" yaml:"hooks"`                        // 日志钩子
+	Name      string       `json:"name" yaml:"name" comment:"日志文件名" default:"app"`                                                                      // 日志文件名
+	Level     string       `json:"level" yaml:"level" comment:"日志级别" default:"info" validate:"oneofci=trace debug info warn warning error fatal panic"` // 默认日志级别
+	Formatter string       `json:"formatter" yaml:"formatter" comment:"日志格式" default:"json" validate:"oneof=text json"`                                 // 默认日志格式
+	Writer    string       `json:"writer" yaml:"writer" comment:"日志输出" default:"console" validate:"oneof=console file"`                                 // 默认日志输出
+	Color     bool         `json:"color" yaml:"color" comment:"是否使用颜色" default:"false"`                                                                 // 使用颜色
+	Caller    bool         `json:"caller" yaml:"caller" comment:"是否输出caller" default:"false"`                                                           // caller开关
+	Hooks     []HookConfig `json:"hooks" yaml:"hooks"`                                                                                                  // 日志钩子
 }
 
 // HookConfig 日志钩子配置
@@ -80,6 +82,7 @@ func (c *Config) Readers() []configx.Reader {
 	return []configx.Reader{
 		nacosx.NewReader("log.yaml"),
 		configx.NewFileReader("log.yaml"),
+		configx.NewEnvReader(configx.EnvPrefix + "_LOG"),
 		configx.NewTagReader(),
 	}
 }
@@ -100,14 +103,37 @@ func (c *Config) Execute() error {
 		}
 	}
 
+	c.apply(formatter)
+	log.WithFields(c.LogFields()).Info("init log success")
+	_config.Store(c)
+	return nil
+}
+
+// OnChange 日志配置变更，日志级别、格式、输出以及caller开关实时生效，日志钩子变更需要重启服务
+func (c *Config) OnChange(old, updated configx.Configurator) error {
+	config, ok := updated.(*Config)
+	if !ok {
+		return errorx.New("unexpected log config type")
+	}
+	if config.Name == "" {
+		config.Name = c.Name
+	}
+	if !reflect.DeepEqual(c.Hooks, config.Hooks) {
+		log.WithFields(config.LogFields()).Warn("log hooks change will take effect after restart")
+		config.Hooks = c.Hooks
+	}
+	config.apply(config.GetFormatter())
+	log.WithFields(config.LogFields()).Info("reload log success")
+	_config.Store(config)
+	return nil
+}
+
+// 应用日志配置
+func (c *Config) apply(formatter log.Formatter) {
 	log.SetFormatter(formatter)        // 设置默认日志格式
 	log.SetLevel(LogrusLevel(c.Level)) // 设置默认日志级别
 	log.SetOutput(c.NewWriter())       // 设置默认日志输出
 	log.SetReportCaller(c.Caller)      // 设置caller开关
-
-	log.WithFields(c.LogFields()).Info("init log success")
-	_config = c
-	return nil
 }
 
 func (c *Config) GetFormatter() log.Formatter {
@@ -126,22 +152,10 @@ func (c *Config) NewWriter() io.Writer {
 	return NewConsoleWriter()
 }
 
-// LogrusLevel 转换日志级别
+// LogrusLevel 转换日志级别，不区分大小写，无法识别时为panic级别
 func LogrusLevel(level string) log.Level {
-	switch strings.ToLower(level) {
-	case LevelTrace:
-		return log.TraceLevel
-	case LevelDebug:
-		return log.DebugLevel
-	case LevelInfo:
-		return log.InfoLevel
-	case LevelError:
-		return log.ErrorLevel
-	case LevelFatal:
-		return log.FatalLevel
-	case LevelPanic:
-		return log.PanicLevel
-	default:
-		return log.PanicLevel
+	if lvl, err := log.ParseLevel(level); err == nil {
+		return lvl
 	}
+	return log.PanicLevel
 }
@@ -15,3 +15,17 @@ func TestLog(t *testing.T) {
 	}
 	log.WithField("test", "test").Info("test")
 }
+
+func TestLevelValidate(t *testing.T) {
+	for _, level := range []string{"warn", "warning", "INFO", "Debug"} {
+		if err := configx.Validate(&Config{Level: level, Formatter: FormatterJson, Writer: WriterConsole}); err != nil {
+			t.Errorf("level %s: expected valid, got %v", level, err)
+		}
+	}
+	if err := configx.Validate(&Config{Level: "verbose"}); err == nil {
+		t.Error("expected invalid level")
+	}
+	if LogrusLevel("WARN") != log.WarnLevel {
+		t.Errorf("unexpected level %s", LogrusLevel("WARN"))
+	}
+}
@@ -9,8 +9,13 @@ import (
 	"go.mongodb.org/mongo-driver/v2/mongo/readpref"
 )
 
-// NewClient 创建客户端
+// NewClient 创建客户端，驱动为空时使用mongo驱动
 func NewClient(config *Config) (*Client, error) {
+	return registry.Build(config.Driver, config)
+}
+
+// MongoClientBuilder mongo客户端构造函数
+func MongoClientBuilder(config *Config) (*Client, error) {
 	client, err := NewMongoClient(config)
 	if err != nil {
 		return nil, errorx.Wrap(err, "create mongodb client failed")
@@ -66,6 +71,13 @@ func (c *Client) Copy(target string, database string) (*Client, error) {
 	return client, nil
 }
 
+func (c *Client) HealthCheck(ctx context.Context) error {
+	if err := c.client.Ping(ctx, readpref.PrimaryPreferred()); err != nil {
+		return errorx.Wrap(err, "ping mongo failed")
+	}
+	return nil
+}
+
 func (c *Client) Close() error {
 	logger := log.WithFields(c.config.LogFields())
 	if err := c.GetClient().Disconnect(context.Background()); err != nil {
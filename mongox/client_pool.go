@@ -5,30 +5,39 @@ import (
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
-// 客户端池
-var pool = configx.NewPool[*Client]()
+// 客户端注册中心，默认使用mongo驱动
+var registry = configx.NewRegistry[*Config, *Client]("mongo", "mongo")
+
+func init() {
+	RegisterClientBuilder("mongo", MongoClientBuilder) // 注册mongo客户端构建器
+}
+
+// RegisterClientBuilder 注册客户端构造函数
+func RegisterClientBuilder(name string, builder ClientBuilder) {
+	registry.Register(name, builder)
+}
+
+// ClientBuilder 客户端构造函数
+type ClientBuilder = configx.Builder[*Config, *Client]
 
 // Pool 获取客户端池
 func Pool() *configx.Pool[*Client] {
-	return pool
+	return registry.Pool()
 }
 
 // Initialized 是否初始化
 func Initialized() bool {
-	return pool.Initialized()
+	return registry.Pool().Initialized()
 }
 
 // AddClient 添加客户端
 func AddClient(source string, client *Client) {
-	if client == nil {
-		return
-	}
-	pool.Add(source, client)
+	registry.Add(source, client)
 }
 
-// GetClient 获取客户端
+// GetClient 获取客户端，未指定 source 时返回 default，数据源不存在时panic
 func GetClient(source ...string) *Client {
-	return pool.Get(source...)
+	return registry.MustGet(source...)
 }
 
 // GetConfig 获取配置
@@ -43,16 +52,11 @@ func GetInstance(source ...string) *mongo.Client {
 
 // GetDatabase 获取数据库
 func GetDatabase(source ...string) *mongo.Database {
-	if client := GetClient(source...); client != nil {
-		database := client.GetConfig().Database
-		return client.GetClient().Database(database)
-	}
-	return nil
+	client := GetClient(source...)
+	return client.GetClient().Database(client.GetConfig().Database)
 }
 
-// Close 关闭所有数据库客户端
+// Close 关闭所有客户端
 func Close() error {
-	return pool.Close(func(client *Client) error {
-		return client.Close()
-	})
+	return registry.Close()
 }
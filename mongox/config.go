@@ -13,19 +13,20 @@ import (
 )
 
 type Config struct {
-	Source          string `json:"source" yaml:"source" default:"default"`                   // 数据源名称
-	Enable          bool   `json:"enable" yaml:"enable"`                                     // 数据源启用
-	Uri             string `json:"uri" yaml:"uri"`                                           // 连接uri
-	AuthMechanism   string `json:"authMechanism" yaml:"authMechanism" default:"SCRAM-SHA-1"` // 认证加密方式
-	AuthSource      string `json:"authSource" yaml:"authSource"`                             // 认证数据库
-	Username        string `json:"username" yaml:"username"`                                 // 用户名
-	Password        string `json:"password" yaml:"password"`                                 // 密码
-	Database        string `json:"database" yaml:"database"`                                 // 数据库名
-	MaxPoolSize     uint64 `json:"maxPoolSize" yaml:"maxPoolSize"`                           // 连接池最大连接数
-	MinPoolSize     uint64 `json:"minPoolSize" yaml:"minPoolSize"`                           // 连接池最小连接数
-	MaxConnIdleTime uint64 `json:"maxConnIdleTime" yaml:"maxConnIdleTime"`                   // 连接池保持空闲连接的最长时间
-	Timeout         uint64 `json:"timeout" yaml:"timeout"`                                   // 超时时间
-	Debug           bool   `json:"debug" yaml:"debug"`                                       // debug模式（日志打印）
+	Source          string `json:"source" yaml:"source" default:"default" comment:"数据源名称"`                    // 数据源名称
+	Driver          string `json:"driver" yaml:"driver" default:"mongo" comment:"客户端驱动"`                      // 客户端驱动
+	Enable          bool   `json:"enable" yaml:"enable" comment:"数据源启用"`                                      // 数据源启用
+	Uri             string `json:"uri" yaml:"uri" validate:"url" secret:"true" comment:"连接uri"`               // 连接uri
+	AuthMechanism   string `json:"authMechanism" yaml:"authMechanism" default:"SCRAM-SHA-1" comment:"认证加密方式"` // 认证加密方式
+	AuthSource      string `json:"authSource" yaml:"authSource" comment:"认证数据库"`                              // 认证数据库
+	Username        string `json:"username" yaml:"username" comment:"用户名"`                                    // 用户名
+	Password        string `json:"password" yaml:"password" secret:"true" comment:"密码"`                       // 密码
+	Database        string `json:"database" yaml:"database" comment:"数据库名"`                                   // 数据库名
+	MaxPoolSize     uint64 `json:"maxPoolSize" yaml:"maxPoolSize" comment:"连接池最大连接数"`                         // 连接池最大连接数
+	MinPoolSize     uint64 `json:"minPoolSize" yaml:"minPoolSize" comment:"连接池最小连接数"`                         // 连接池最小连接数
+	MaxConnIdleTime uint64 `json:"maxConnIdleTime" yaml:"maxConnIdleTime" comment:"连接池保持空闲连接的最长时间"`           // 连接池保持空闲连接的最长时间
+	Timeout         uint64 `json:"timeout" yaml:"timeout" comment:"超时时间"`                                     // 超时时间
+	Debug           bool   `json:"debug" yaml:"debug" comment:"debug模式（日志打印）"`                                // debug模式（日志打印）
 }
 
 // ClientOptions 连接选项
@@ -64,6 +65,7 @@ func (c *Config) ClientOptions() *options.ClientOptions {
 func (c *Config) Copy() *Config {
 	return &Config{
 		Source:          c.Source,
+		Driver:          c.Driver,
 		Enable:          c.Enable,
 		Uri:             c.Uri,
 		AuthMechanism:   c.AuthMechanism,
@@ -83,7 +85,8 @@ func (c *Config) Copy() *Config {
 func (c *Config) LogFields() map[string]interface{} {
 	fields := make(map[string]interface{})
 	fields["source"] = c.Source
-	fields["uri"] = c.Uri
+	fields["builder"] = c.Driver
+	fields["uri"] = configx.MaskSecret(c.Uri)
 	fields["database"] = c.Database
 	fields["debug"] = c.Debug
 	return fields
@@ -93,6 +96,7 @@ func (c *Config) Readers() []configx.Reader {
 	return []configx.Reader{
 		nacosx.NewReader("mongo.yaml"),
 		configx.NewFileReader("mongo.yaml"),
+		configx.NewEnvReader(configx.EnvPrefix + "_MONGO"),
 	}
 }
 
@@ -101,6 +105,11 @@ func (c *Config) Valid() bool {
 }
 
 func (c *Config) Execute() error {
+	return c.ExecuteWith(registry.Pool())
+}
+
+// ExecuteWith 创建客户端并添加到指定的客户端池
+func (c *Config) ExecuteWith(p *configx.Pool[*Client]) error {
 	if c.Enable {
 		logger := log.WithFields(c.LogFields())
 		client, err := NewClient(c)
@@ -109,7 +118,7 @@ func (c *Config) Execute() error {
 			return errorx.Wrap(err, "create mongo client failed")
 		}
 		logger.Info("init mongo success")
-		AddClient(c.Source, client)
+		p.Add(c.Source, client)
 	}
 	return nil
 }
@@ -124,16 +133,22 @@ func (s Configs) Readers() []configx.Reader {
 	return []configx.Reader{
 		nacosx.NewReader("mongo.yaml"),
 		configx.NewFileReader("mongo.yaml"),
+		configx.NewEnvReader(configx.EnvPrefix + "_MONGO"),
 	}
 }
 
 func (s Configs) Execute() error {
+	return s.ExecuteWith(registry.Pool())
+}
+
+// ExecuteWith 创建客户端并添加到指定的客户端池
+func (s Configs) ExecuteWith(p *configx.Pool[*Client]) error {
 	for _, config := range s {
-		if err := config.Execute(); err != nil {
+		if err := config.ExecuteWith(p); err != nil {
 			return errorx.Wrap(err, "mongo config execute failed")
 		}
 	}
-	if !Initialized() {
+	if !p.Initialized() {
 		err := errorx.New("no enabled mongo source")
 		log.WithField("error", err.Error()).Warn("init mongo failed")
 		return err
@@ -1,7 +1,9 @@
 package nacosx
 
 import (
+	"context"
 	"reflect"
+	"sync"
 
 	"github.com/go-xuan/utilx/errorx"
 	"github.com/go-xuan/utilx/marshalx"
@@ -10,6 +12,8 @@ import (
 	"github.com/nacos-group/nacos-sdk-go/model"
 	"github.com/nacos-group/nacos-sdk-go/vo"
 	log "github.com/sirupsen/logrus"
+
+	"github.com/go-xuan/quanx/configx"
 )
 
 // 客户端
@@ -134,18 +138,35 @@ func (c *Client) ReadConfig(config any, param vo.ConfigParam) ([]byte, error) {
 	return data, nil
 }
 
-// ListenConfig 监听nacos配置
-func (c *Client) ListenConfig(config any, param vo.ConfigParam) error {
+// ListenConfig 监听nacos配置，配置必须实现 configx.Reloadable 接口
+// 配置变更时读取至新的配置实例并回调变更前配置的OnChange，不会直接修改变更前的配置
+func (c *Client) ListenConfig(config configx.Reloadable, param vo.ConfigParam) error {
+	typ := reflect.TypeOf(config)
+	if typ == nil || typ.Kind() != reflect.Pointer {
+		return errorx.New("the listened config must be of pointer type")
+	}
+	var mu sync.Mutex
+	current := config
 	// 配置监听响应方法
 	param.OnChange = func(namespace, group, dataId, data string) {
+		mu.Lock()
+		defer mu.Unlock()
 		logger := log.WithField("dataId", dataId).
 			WithField("group", group).
-			WithField("namespace", namespace).
-			WithField("data", data)
+			WithField("namespace", namespace)
 		logger.Info("the nacos config data has changed !!!")
-		if err := marshalx.Apply(dataId).Unmarshal([]byte(data), config); err != nil {
+		updated, _ := reflect.New(typ.Elem()).Interface().(configx.Reloadable)
+		if err := marshalx.Apply(dataId).Unmarshal([]byte(data), updated); err != nil {
 			logger.WithError(err).Error("update nacos config failed")
+			return
+		} else if !updated.Valid() {
+			logger.Warn("updated nacos config is invalid, change discarded")
+			return
+		} else if err = current.OnChange(current, updated); err != nil {
+			logger.WithError(err).Error("apply nacos config change failed, change discarded")
+			return
 		}
+		current = updated
 	}
 	if err := c.GetConfigClient().ListenConfig(param); err != nil {
 		return errorx.Wrap(err, "listen nacos config failed")
@@ -169,3 +190,36 @@ func (c *Client) SearchConfig(param vo.SearchConfigParam) (*model.ConfigPage, er
 	}
 	return page, nil
 }
+
+// HealthCheck 健康检查，优先通过服务发现客户端检查，未启用服务发现时通过配置中心客户端检查
+func (c *Client) HealthCheck(ctx context.Context) error {
+	done := make(chan error, 1)
+	go func() {
+		var err error
+		if c.namingClient != nil {
+			_, err = c.namingClient.GetAllServicesInfo(vo.GetAllServiceInfoParam{
+				GroupName: c.config.Group,
+				PageNo:    1,
+				PageSize:  1,
+			})
+		} else if c.configClient != nil {
+			_, err = c.configClient.SearchConfig(vo.SearchConfigParam{
+				Search:   "blur",
+				Group:    c.config.Group,
+				PageNo:   1,
+				PageSize: 1,
+			})
+		}
+		done <- err
+	}()
+	// nacos客户端不支持ctx，超时后直接返回
+	select {
+	case err := <-done:
+		if err != nil {
+			return errorx.Wrap(err, "nacos health check failed")
+		}
+		return nil
+	case <-ctx.Done():
+		return errorx.Wrap(ctx.Err(), "nacos health check timeout")
+	}
+}
@@ -23,14 +23,14 @@ const (
 
 // Config nacos连接配置
 type Config struct {
-	Address   string `yaml:"address" json:"address"`      // nacos服务地址,多个以英文逗号分割
-	Username  string `yaml:"username" json:"username"`    // 用户名
-	Password  string `yaml:"password" json:"password"`    // 密码
-	AccessKey string `yaml:"access_key" json:"accessKey"` // ak
-	SecretKey string `yaml:"secret_key" json:"secretKey"` // sk
-	Namespace string `yaml:"namespace" json:"namespace"`  // 命名空间
-	Group     string `yaml:"group" json:"group"`          // 配置分组
-	Mode      int    `yaml:"mode" json:"mode"`            // 模式（0-仅配置中心；1-仅服务发现；2-配置中心和服务发现）
+	Address   string `yaml:"address" json:"address" validate:"required" comment:"nacos服务地址,多个以英文逗号分割"`          // nacos服务地址,多个以英文逗号分割
+	Username  string `yaml:"username" json:"username" comment:"用户名"`                                            // 用户名
+	Password  string `yaml:"password" json:"password" secret:"true" comment:"密码"`                               // 密码
+	AccessKey string `yaml:"access_key" json:"accessKey" comment:"ak"`                                          // ak
+	SecretKey string `yaml:"secret_key" json:"secretKey" secret:"true" comment:"sk"`                            // sk
+	Namespace string `yaml:"namespace" json:"namespace" validate:"required" comment:"命名空间"`                     // 命名空间
+	Group     string `yaml:"group" json:"group" validate:"required" comment:"配置分组"`                             // 配置分组
+	Mode      int    `yaml:"mode" json:"mode" validate:"oneof=0 1 2" comment:"模式（0-仅配置中心；1-仅服务发现；2-配置中心和服务发现）"` // 模式（0-仅配置中心；1-仅服务发现；2-配置中心和服务发现）
 }
 
 // LogFields 日志字段
@@ -46,6 +46,7 @@ func (c *Config) LogFields() map[string]interface{} {
 func (c *Config) Readers() []configx.Reader {
 	return []configx.Reader{
 		configx.NewFileReader("nacos.yaml"),
+		configx.NewEnvReader(configx.EnvPrefix + "_NACOS"),
 	}
 }
 
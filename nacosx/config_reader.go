@@ -2,11 +2,15 @@ package nacosx
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/go-xuan/utilx/errorx"
 	"github.com/go-xuan/utilx/filex"
 	"github.com/go-xuan/utilx/marshalx"
 	"github.com/nacos-group/nacos-sdk-go/vo"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/go-xuan/quanx/configx"
 )
 
 // NewReader 创建nacos配置读取器
@@ -15,20 +19,32 @@ func NewReader(dataId string) *Reader {
 }
 
 // Reader nacos配置读取器
+// 当前环境不为空时，dataId-profile.ext 将深度合并到 dataId.ext 之上，合并规则参考 configx.MergeLayers
 type Reader struct {
-	DataId string `json:"dataId"` // 配置文件id
-	Group  string `json:"group"`  // 配置所在分组
-	Type   string `json:"type"`   // 配置文件类型
-	Data   []byte `json:"data"`   // 配置文件内容
-	Listen bool   `json:"listen"` // 是否启用监听
+	DataId  string            `json:"dataId"`  // 配置文件id
+	Group   string            `json:"group"`   // 配置所在分组
+	Type    string            `json:"type"`    // 配置文件类型
+	Profile string            `json:"profile"` // 环境，为空时使用当前环境
+	Data    []byte            `json:"data"`    // 配置文件内容（合并后）
+	Listen  bool              `json:"listen"`  // 是否启用监听，仅对实现 configx.Reloadable 的配置器生效，由configx统一监听并热加载
+	mu      sync.Mutex        // 缓存锁，Watch 在nacos回调协程中清除缓存
+	origins map[string]string // 字段来源
 }
 
 func (r *Reader) ConfigParam() vo.ConfigParam {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	param := r.configParam(r.DataId)
+	param.Content = string(r.Data)
+	return param
+}
+
+// 指定配置文件id的配置参数，不包含配置内容
+func (r *Reader) configParam(dataId string) vo.ConfigParam {
 	return vo.ConfigParam{
-		DataId:  r.DataId,
-		Group:   r.Group,
-		Content: string(r.Data),
-		Type:    vo.ConfigType(r.GetType()),
+		DataId: dataId,
+		Group:  r.Group,
+		Type:   vo.ConfigType(r.GetType()),
 	}
 }
 
@@ -46,35 +62,121 @@ func (r *Reader) Anchor(group string) {
 }
 
 // Read 从nacos中读取配置
+// 配置变更仅热加载实现 configx.Reloadable 的配置器，其他配置器不会被直接写入，避免并发读取时读取到写入一半的配置
 func (r *Reader) Read(v any) error {
-	if r.Data == nil {
-		if !Initialized() {
-			return errorx.New("nacos not initialized")
-		}
+	if _, reloadable := v.(configx.Reloadable); r.Listen && !reloadable {
+		log.WithField("dataId", r.DataId).Warn("nacos config listen only takes effect for configx.Reloadable configurators")
+	}
+	data, err := r.load()
+	if err != nil {
+		return errorx.Wrap(err, "read nacos config failed")
+	}
+	if err = marshalx.Apply(r.GetType()).Unmarshal(data, v); err != nil {
+		return errorx.Wrap(err, "unmarshal nacos config failed")
+	}
+	return nil
+}
+
+// Origins 获取各字段的来源配置，key为字段路径
+func (r *Reader) Origins() map[string]string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.origins
+}
+
+// GetProfile 获取环境
+func (r *Reader) GetProfile() string {
+	if r.Profile != "" {
+		return r.Profile
+	}
+	return configx.GetProfile()
+}
+
+// 配置文件id，按照合并顺序排列
+func (r *Reader) dataIds() []string {
+	dataIds := []string{r.DataId}
+	if profile := r.GetProfile(); profile != "" {
+		dataIds = append(dataIds, configx.ProfileName(r.DataId, profile))
+	}
+	return dataIds
+}
 
-		// 配置文件锚点为group分组
-		r.Anchor(GetClient().GetGroup())
+// 读取并合并配置，已读取过的配置直接使用缓存
+func (r *Reader) load() ([]byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Data != nil {
+		return r.Data, nil
+	}
+	if !Initialized() {
+		return nil, errorx.New("nacos not initialized")
+	}
+	// 配置文件锚点为group分组
+	r.Anchor(GetClient().GetGroup())
 
-		// 读取配置
-		param := r.ConfigParam()
-		data, err := GetClient().ReadConfig(v, param)
+	var layers []configx.Layer
+	for _, dataId := range r.dataIds() {
+		content, err := GetClient().GetConfig(r.configParam(dataId), false)
 		if err != nil {
-			return errorx.Wrap(err, "read nacos config failed")
+			return nil, errorx.Wrap(err, "get nacos config failed")
+		} else if content != "" {
+			layers = append(layers, configx.Layer{
+				Location: fmt.Sprintf("nacos@%s@%s", r.Group, dataId),
+				Data:     []byte(content),
+			})
 		}
-		r.Data = data
+	}
+	if len(layers) == 0 {
+		return nil, errorx.New("read nacos config empty")
+	}
+	data, origins, err := configx.MergeLayers(r.GetType(), layers...)
+	if err != nil {
+		return nil, errorx.Wrap(err, "merge nacos config failed")
+	}
+	r.Data, r.origins = data, origins
+	return data, nil
+}
 
-		if r.Listen {
-			// 监听配置变化
-			if err = GetClient().ListenConfig(v, param); err != nil {
-				return errorx.Wrap(err, "listen nacos config failed")
+// Watch 监听配置变更（包括环境配置），配置变更时清除缓存并回调onChange
+func (r *Reader) Watch(onChange func()) (func(), error) {
+	if !Initialized() {
+		return nil, errorx.New("nacos not initialized")
+	}
+	r.Anchor(GetClient().GetGroup())
+	var params []vo.ConfigParam
+	stop := func() {
+		for _, param := range params {
+			if err := GetClient().CancelListenConfig(param); err != nil {
+				log.WithField("dataId", param.DataId).WithError(err).Warn("cancel listen nacos config failed")
 			}
 		}
 	}
-	return nil
+	for _, dataId := range r.dataIds() {
+		param := r.configParam(dataId)
+		param.OnChange = func(namespace, group, dataId, data string) {
+			log.WithField("dataId", dataId).
+				WithField("group", group).
+				WithField("namespace", namespace).
+				Info("the nacos config data has changed !!!")
+			r.mu.Lock()
+			r.Data = nil
+			r.mu.Unlock()
+			onChange()
+		}
+		if err := GetClient().GetConfigClient().ListenConfig(param); err != nil {
+			stop()
+			return nil, errorx.Wrap(err, "listen nacos config failed")
+		}
+		params = append(params, param)
+	}
+	return stop, nil
 }
 
 // Location 配置文件位置
 func (r *Reader) Location() string {
+	if profile := r.GetProfile(); profile != "" {
+		return fmt.Sprintf("nacos@%s@%s@%s", r.Group, r.DataId, profile)
+	}
 	return fmt.Sprintf("nacos@%s@%s", r.Group, r.DataId)
 }
 
@@ -89,7 +191,9 @@ func (r *Reader) Write(v any) error {
 	if err != nil {
 		return errorx.Wrap(err, "marshal config failed")
 	}
+	r.mu.Lock()
 	r.Data = data
+	r.mu.Unlock()
 
 	// 配置文件锚点为group分组
 	r.Anchor(GetClient().GetGroup())
@@ -6,14 +6,10 @@ import (
 	"time"
 
 	"github.com/go-xuan/quanx/configx"
-	"github.com/go-xuan/typex"
-	"github.com/go-xuan/utilx/errorx"
 )
 
-var (
-	pool     = configx.NewPool[Client]()        // 客户端池
-	builders *typex.Enum[string, ClientBuilder] // 客户端构造函数池
-)
+// 客户端注册中心，默认使用minio
+var registry = configx.NewRegistry[*Config, Client]("oss", "minio")
 
 func init() {
 	RegisterClientBuilder("minio", MinioClientBuilder) // 注册minio客户端构建器
@@ -21,27 +17,16 @@ func init() {
 
 // RegisterClientBuilder 注册客户端构造函数
 func RegisterClientBuilder(name string, builder ClientBuilder) {
-	if builders == nil {
-		builders = typex.NewStringEnum[ClientBuilder]()
-	}
-	builders.Add(name, builder)
+	registry.Register(name, builder)
 }
 
-// NewClient 创建客户端
+// NewClient 创建客户端，驱动为空时使用minio
 func NewClient(config *Config) (Client, error) {
-	if config.Driver == "" {
-		return MinioClientBuilder(config)
-	}
-	if builders != nil {
-		if builder, ok := builders.Find(config.Driver); ok && builder != nil {
-			return builder(config)
-		}
-	}
-	return nil, errorx.Sprintf("client driver is not registered: %s", config.Driver)
+	return registry.Build(config.Driver, config)
 }
 
 // ClientBuilder 客户端构造函数
-type ClientBuilder func(*Config) (Client, error)
+type ClientBuilder = configx.Builder[*Config, Client]
 
 // Client oss客户端
 type Client interface {
@@ -49,6 +34,8 @@ type Client interface {
 	GetConfig() *Config // 获取oss配置
 	Close() error       // 关闭客户端
 
+	HealthCheck(ctx context.Context) error // 健康检查
+
 	CreateBucket(ctx context.Context, name string, options ...any) error                           // 创建bucket
 	Upload(ctx context.Context, key string, reader io.Reader, options ...any) error                // 上传文件
 	Get(ctx context.Context, key string, options ...any) (io.ReadCloser, error)                    // 获取文件
@@ -58,30 +45,32 @@ type Client interface {
 	GetUrl(ctx context.Context, key string, expires time.Duration, options ...any) (string, error) // 获取文件url
 }
 
+// DefaultPool 获取默认客户端池，与 Pool 不同的是，客户端池未初始化时不会panic
+func DefaultPool() *configx.Pool[Client] {
+	return registry.Pool()
+}
+
 // Pool 获取客户端池
 func Pool() *configx.Pool[Client] {
 	if !Initialized() {
-		panic("client pool not initialized")
+		panic("oss client pool not initialized")
 	}
-	return pool
+	return registry.Pool()
 }
 
 // Initialized 是否初始化
 func Initialized() bool {
-	return pool.Initialized()
+	return registry.Pool().Initialized()
 }
 
 // AddClient 添加客户端
 func AddClient(source string, client Client) {
-	if any(client) == nil {
-		return
-	}
-	pool.Add(source, client)
+	registry.Add(source, client)
 }
 
-// GetClient 获取客户端
+// GetClient 获取客户端，未指定 source 时返回 default，数据源不存在时panic
 func GetClient(source ...string) Client {
-	return pool.Get(source...)
+	return registry.MustGet(source...)
 }
 
 // GetConfig 获取配置
@@ -100,7 +89,5 @@ func GetInstance[T any](source ...string) T {
 
 // Close 关闭所有客户端
 func Close() error {
-	return pool.Close(func(client Client) error {
-		return client.Close()
-	})
+	return registry.Close()
 }
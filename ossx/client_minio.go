@@ -63,6 +63,17 @@ func (c *MinioClient) Close() error {
 	return nil
 }
 
+func (c *MinioClient) HealthCheck(ctx context.Context) error {
+	if bucket := c.config.Bucket; bucket != "" {
+		if _, err := c.client.BucketExists(ctx, bucket); err != nil {
+			return errorx.Wrap(err, "check minio bucket exists failed")
+		}
+	} else if _, err := c.client.ListBuckets(ctx); err != nil {
+		return errorx.Wrap(err, "list minio buckets failed")
+	}
+	return nil
+}
+
 func (c *MinioClient) CreateBucket(ctx context.Context, bucket string, options ...any) error {
 	if exist, err := c.GetClient().BucketExists(ctx, bucket); err != nil {
 		return errorx.Wrap(err, "check minio bucket exists failed")
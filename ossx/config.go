@@ -9,15 +9,15 @@ import (
 )
 
 type Config struct {
-	Source          string `json:"source" yaml:"source"`                   // oss源名称
-	Driver          string `json:"driver" yaml:"driver" default:"minio"`   // 客户端驱动
-	Enable          bool   `json:"enable" yaml:"enable"`                   // 启用
-	Endpoint        string `json:"endpoint" yaml:"endpoint"`               // 主机
-	AccessKeyId     string `json:"accessKeyId" yaml:"accessKeyId"`         // 访问id
-	AccessKeySecret string `json:"accessKeySecret" yaml:"accessKeySecret"` // 访问秘钥
-	AccessToken     string `json:"accessToken" json:"accessToken"`         // 访问token
-	Secure          bool   `json:"secure" yaml:"secure"`                   // 是否使用https
-	Bucket          string `json:"bucket" yaml:"bucket"`                   // 桶名
+	Source          string `json:"source" yaml:"source" comment:"oss源名称"`                               // oss源名称
+	Driver          string `json:"driver" yaml:"driver" default:"minio" comment:"客户端驱动"`                // 客户端驱动
+	Enable          bool   `json:"enable" yaml:"enable" comment:"启用"`                                   // 启用
+	Endpoint        string `json:"endpoint" yaml:"endpoint" comment:"主机"`                               // 主机
+	AccessKeyId     string `json:"accessKeyId" yaml:"accessKeyId" comment:"访问id"`                       // 访问id
+	AccessKeySecret string `json:"accessKeySecret" yaml:"accessKeySecret" secret:"true" comment:"访问秘钥"` // 访问秘钥
+	AccessToken     string `json:"accessToken" yaml:"accessToken" secret:"true" comment:"访问token"`      // 访问token
+	Secure          bool   `json:"secure" yaml:"secure" comment:"是否使用https"`                            // 是否使用https
+	Bucket          string `json:"bucket" yaml:"bucket" comment:"桶名"`                                   // 桶名
 }
 
 func (c *Config) LogFields() map[string]interface{} {
@@ -37,11 +37,17 @@ func (c *Config) Readers() []configx.Reader {
 	return []configx.Reader{
 		nacosx.NewReader("oss.yaml"),
 		configx.NewFileReader("oss.yaml"),
+		configx.NewEnvReader(configx.EnvPrefix + "_OSS"),
 		configx.NewTagReader(),
 	}
 }
 
 func (c *Config) Execute() error {
+	return c.ExecuteWith(registry.Pool())
+}
+
+// ExecuteWith 创建客户端并添加到指定的客户端池
+func (c *Config) ExecuteWith(p *configx.Pool[Client]) error {
 	if c.Enable {
 		logger := log.WithFields(c.LogFields())
 		client, err := NewClient(c)
@@ -50,7 +56,7 @@ func (c *Config) Execute() error {
 			return errorx.Wrap(err, "create oss client failed")
 		}
 		logger.Info("init oss success")
-		AddClient(c.Source, client)
+		p.Add(c.Source, client)
 	}
 	return nil
 }
@@ -65,16 +71,22 @@ func (s Configs) Readers() []configx.Reader {
 	return []configx.Reader{
 		nacosx.NewReader("oss.yaml"),
 		configx.NewFileReader("oss.yaml"),
+		configx.NewEnvReader(configx.EnvPrefix + "_OSS"),
 	}
 }
 
 func (s Configs) Execute() error {
+	return s.ExecuteWith(registry.Pool())
+}
+
+// ExecuteWith 创建客户端并添加到指定的客户端池
+func (s Configs) ExecuteWith(p *configx.Pool[Client]) error {
 	for _, config := range s {
-		if err := config.Execute(); err != nil {
+		if err := config.ExecuteWith(p); err != nil {
 			return errorx.Wrap(err, "oss config execute failed")
 		}
 	}
-	if !Initialized() {
+	if !p.Initialized() {
 		err := errorx.New("no enabled oss source")
 		log.WithField("error", err.Error()).Warn("init oss failed")
 		return err
@@ -26,6 +26,11 @@ type Center interface {
 	SelectAll(name string) ([]Instance, error) // 获取全部服务实例
 }
 
+// Initialized 服务中心是否已初始化
+func Initialized() bool {
+	return _center != nil
+}
+
 func getCenter() Center {
 	if _center == nil {
 		panic("server center not initialized")
@@ -17,9 +17,9 @@ func DefaultConfig() *Config {
 
 // Config 服务运行配置
 type Config struct {
-	Name string         `json:"name" yaml:"name"` // 服务名称
-	Host string         `json:"host" yaml:"host"` // host, 为空时默认获取本地IP
-	Port map[string]int `json:"port" yaml:"port"` // 服务端口, 键为服务类型, 值为端口号
+	Name string         `json:"name" yaml:"name" comment:"服务名称"`                      // 服务名称
+	Host string         `json:"host" yaml:"host" comment:"服务host，为空时默认获取本地IP"`        // host, 为空时默认获取本地IP
+	Port map[string]int `json:"port" yaml:"port" comment:"服务端口，key为服务类型，例如http/grpc"` // 服务端口, 键为服务类型, 值为端口号
 }
 
 // Cover 覆盖配置，仅合并非空字段
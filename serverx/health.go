@@ -0,0 +1,198 @@
+package serverx
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-xuan/typex"
+	"github.com/go-xuan/utilx/errorx"
+)
+
+const (
+	HealthPath = "/healthz" // 存活检查路径
+	ReadyPath  = "/readyz"  // 就绪检查路径
+
+	StatusUp       = "up"       // 正常
+	StatusDown     = "down"     // 异常
+	StatusDegraded = "degraded" // 部分组件异常
+
+	DefaultHealthTimeout = 3 * time.Second // 默认健康检查超时时间
+)
+
+// HealthChecker 健康检查接口
+type HealthChecker interface {
+	HealthCheck(ctx context.Context) error
+}
+
+// HealthCheckFunc 健康检查函数
+type HealthCheckFunc func(ctx context.Context) error
+
+func (f HealthCheckFunc) HealthCheck(ctx context.Context) error {
+	return f(ctx)
+}
+
+// HealthBinder 支持绑定健康检查的服务
+type HealthBinder interface {
+	BindHealth(health *Health)
+}
+
+// NewHealth 创建健康检查注册中心
+func NewHealth() *Health {
+	return &Health{
+		checkers: typex.NewStringEnum[HealthChecker](),
+		timeout:  DefaultHealthTimeout,
+	}
+}
+
+// Health 健康检查注册中心，汇总各组件的健康状态
+type Health struct {
+	checkers *typex.Enum[string, HealthChecker] // 健康检查器
+	timeout  time.Duration                      // 单个组件检查超时时间
+	ready    atomic.Bool                        // 就绪状态
+}
+
+// Register 注册健康检查器，同名检查器会被覆盖
+func (h *Health) Register(name string, checker HealthChecker) {
+	if name != "" && checker != nil {
+		h.checkers.Add(name, checker)
+	}
+}
+
+// Deregister 注销健康检查器
+func (h *Health) Deregister(name string) {
+	h.checkers.Remove(name)
+}
+
+// SetTimeout 设置单个组件检查超时时间
+func (h *Health) SetTimeout(timeout time.Duration) {
+	if timeout > 0 {
+		h.timeout = timeout
+	}
+}
+
+// SetReady 设置就绪状态，服务开始关闭时应立即设置为false，使负载均衡停止转发流量
+func (h *Health) SetReady(ready bool) {
+	h.ready.Store(ready)
+}
+
+// Ready 是否就绪
+func (h *Health) Ready() bool {
+	return h.ready.Load()
+}
+
+// Check 并发执行全部健康检查
+func (h *Health) Check(ctx context.Context) *HealthReport {
+	var names []string
+	var checkers []HealthChecker
+	h.checkers.Range(func(name string, checker HealthChecker) bool {
+		names, checkers = append(names, name), append(checkers, checker)
+		return true
+	})
+	// 按照注册顺序输出检查结果
+	components := make([]*ComponentHealth, len(names))
+	var wg sync.WaitGroup
+	for i := range names {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			components[i] = h.check(ctx, names[i], checkers[i])
+		}(i)
+	}
+	wg.Wait()
+
+	report := &HealthReport{Status: StatusUp, Ready: h.Ready(), Components: components}
+	for _, component := range components {
+		if component.Status != StatusUp {
+			report.Status = StatusDegraded
+			break
+		}
+	}
+	return report
+}
+
+// 执行单个组件的健康检查
+func (h *Health) check(ctx context.Context, name string, checker HealthChecker) *ComponentHealth {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- checker.HealthCheck(ctx)
+	}()
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = errorx.Wrap(ctx.Err(), "health check timeout")
+	}
+	result := &ComponentHealth{
+		Name:    name,
+		Status:  StatusUp,
+		Latency: time.Since(start).String(),
+	}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// HealthHandler 存活检查，进程可响应即返回200，不执行组件健康检查，避免数据库、redis等依赖异常时进程被重启
+func (h *Health) HealthHandler(w http.ResponseWriter, _ *http.Request) {
+	writeHealthReport(w, http.StatusOK, &HealthReport{Status: StatusUp, Ready: h.Ready()})
+}
+
+// ReadyHandler 就绪检查，服务未就绪或者任一组件异常时返回503，响应体中包含各组件健康状态
+func (h *Health) ReadyHandler(w http.ResponseWriter, r *http.Request) {
+	if !h.Ready() {
+		writeHealthReport(w, http.StatusServiceUnavailable, &HealthReport{Status: StatusDown})
+		return
+	}
+	code, report := http.StatusOK, h.Check(r.Context())
+	if report.Status != StatusUp {
+		code = http.StatusServiceUnavailable
+	}
+	writeHealthReport(w, code, report)
+}
+
+// Wrap 包装http处理器，拦截健康检查路径，handler为空时使用 http.DefaultServeMux
+func (h *Health) Wrap(handler http.Handler) http.Handler {
+	if handler == nil {
+		handler = http.DefaultServeMux
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case HealthPath:
+			h.HealthHandler(w, r)
+		case ReadyPath:
+			h.ReadyHandler(w, r)
+		default:
+			handler.ServeHTTP(w, r)
+		}
+	})
+}
+
+func writeHealthReport(w http.ResponseWriter, code int, report *HealthReport) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(report)
+}
+
+// HealthReport 健康检查报告
+type HealthReport struct {
+	Status     string             `json:"status"`     // 整体状态
+	Ready      bool               `json:"ready"`      // 是否就绪
+	Components []*ComponentHealth `json:"components"` // 各组件健康状态
+}
+
+// ComponentHealth 组件健康状态
+type ComponentHealth struct {
+	Name    string `json:"name"`            // 组件名称
+	Status  string `json:"status"`          // 状态
+	Latency string `json:"latency"`         // 检查耗时
+	Error   string `json:"error,omitempty"` // 异常信息
+}
@@ -0,0 +1,52 @@
+package serverx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHealth(t *testing.T) {
+	health := NewHealth()
+	health.Register("ok", HealthCheckFunc(func(context.Context) error { return nil }))
+	health.Register("fail", HealthCheckFunc(func(context.Context) error { return errors.New("unavailable") }))
+
+	report := health.Check(context.Background())
+	if report.Status != StatusDegraded || len(report.Components) != 2 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	if report.Components[0].Name != "ok" || report.Components[1].Status != StatusDown {
+		t.Errorf("unexpected components: %+v, %+v", report.Components[0], report.Components[1])
+	}
+
+	handler := health.Wrap(http.NotFoundHandler())
+	serve := func(path string) int {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		return recorder.Code
+	}
+	// 存活检查不受组件异常影响，也不执行组件健康检查
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, HealthPath, nil))
+	if recorder.Code != http.StatusOK || strings.Contains(recorder.Body.String(), "unavailable") {
+		t.Errorf("healthz: expected 200 without component checks, got %d %s", recorder.Code, recorder.Body.String())
+	}
+	if code := serve(ReadyPath); code != http.StatusServiceUnavailable {
+		t.Errorf("readyz before ready: expected 503, got %d", code)
+	}
+	health.Deregister("fail")
+	health.SetReady(true)
+	if code := serve(ReadyPath); code != http.StatusOK {
+		t.Errorf("readyz: expected 200, got %d", code)
+	}
+	health.SetReady(false)
+	if code := serve(ReadyPath); code != http.StatusServiceUnavailable {
+		t.Errorf("readyz after shutdown: expected 503, got %d", code)
+	}
+	if code := serve("/other"); code != http.StatusNotFound {
+		t.Errorf("other: expected 404, got %d", code)
+	}
+}
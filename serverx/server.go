@@ -3,6 +3,7 @@ package serverx
 import (
 	"context"
 
+	"github.com/go-xuan/utilx/errorx"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -19,6 +20,11 @@ type Server interface {
 	Shutdown(ctx context.Context)    // 关闭服务
 }
 
+// ErrorReporter 支持上报运行异常的服务
+type ErrorReporter interface {
+	BindErrorChan(errs chan<- error) // 绑定异常通道，服务运行异常时将错误发送至该通道
+}
+
 // NewBase 创建基础服务
 func NewBase(category string, port ...int) Base {
 	base := Base{category: category}
@@ -30,11 +36,28 @@ func NewBase(category string, port ...int) Base {
 
 // Base 基础服务配置
 type Base struct {
-	name     string     // 服务名称
-	category string     // 服务分类
-	port     int        // 服务端口
-	running  bool       // 服务运行标识
-	logger   *log.Entry // 日志记录器
+	name     string       // 服务名称
+	category string       // 服务分类
+	port     int          // 服务端口
+	running  bool         // 服务运行标识
+	logger   *log.Entry   // 日志记录器
+	errs     chan<- error // 运行异常通道
+}
+
+// BindErrorChan 绑定异常通道
+func (s *Base) BindErrorChan(errs chan<- error) {
+	s.errs = errs
+}
+
+// 上报服务运行异常，通道已满时仅记录日志
+func (s *Base) reportError(err error) {
+	s.logger.WithError(err).Error("server run failed")
+	if s.errs != nil {
+		select {
+		case s.errs <- errorx.Wrap(err, s.category+" server run failed"):
+		default:
+		}
+	}
 }
 
 // 绑定服务配置
@@ -57,16 +80,21 @@ func (s *Base) bindConfig(config *Config) {
 	})
 }
 
+// Start 依次启动服务，任一服务启动失败时按照逆序关闭已启动的服务并返回错误
 func Start(ctx context.Context, config *Config, servers ...Server) error {
-	for _, server := range servers {
+	for i, server := range servers {
 		server.BindConfig(config)
 		if err := server.Start(ctx); err != nil {
+			for j := i - 1; j >= 0; j-- {
+				servers[j].Shutdown(ctx)
+			}
 			return err
 		}
 	}
 	return nil
 }
 
+// Shutdown 依次关闭服务
 func Shutdown(ctx context.Context, servers ...Server) {
 	for _, server := range servers {
 		server.Shutdown(ctx)
@@ -2,6 +2,7 @@ package serverx
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 
@@ -41,8 +42,8 @@ func (s *GrpcServer) Start(_ context.Context) error {
 	}
 	go func() {
 		// 启动服务（非阻塞）
-		if err = s.server.Serve(listen); err != nil {
-			s.logger.WithError(err).Fatal("start server failed")
+		if err := s.server.Serve(listen); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			s.reportError(err)
 		}
 	}()
 	s.logger.Info("start server success")
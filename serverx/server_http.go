@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 
 	"github.com/go-xuan/utilx/errorx"
@@ -32,8 +33,10 @@ func NewPprofServer(port ...int) *HttpServer {
 
 // HttpServer http服务
 type HttpServer struct {
-	Base                // 基础服务配置
-	server *http.Server // http服务
+	Base                 // 基础服务配置
+	server  *http.Server // http服务
+	handler http.Handler // 未包装健康检查的原始处理器
+	health  *Health      // 健康检查
 }
 
 // IsRunning 是否运行中
@@ -46,17 +49,37 @@ func (s *HttpServer) BindConfig(config *Config) {
 	s.bindConfig(config)
 }
 
+// BindHealth 绑定健康检查，http服务将额外提供 /healthz 以及 /readyz 接口
+func (s *HttpServer) BindHealth(health *Health) {
+	if s.category == HTTP && health != nil {
+		s.health = health
+	}
+}
+
 func (s *HttpServer) Start(_ context.Context) error {
 	if s.running {
 		return nil
 	} else if s.port == 0 {
 		return errorx.New("server port is invalid")
 	}
+	// 基于原始处理器包装，重复启动时不会重复包装
+	if s.health != nil {
+		if s.handler == nil {
+			s.handler = s.server.Handler
+		}
+		s.server.Handler = s.health.Wrap(s.handler)
+	}
+	// 同步监听端口，端口冲突等错误直接返回
+	addr := fmt.Sprintf(":%d", s.port)
+	listen, err := net.Listen("tcp", addr)
+	if err != nil {
+		return errorx.Wrap(err, "listen error "+addr)
+	}
+	s.server.Addr = addr
 	go func() {
 		// 启动服务（非阻塞）
-		s.server.Addr = fmt.Sprintf(":%d", s.port)
-		if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-			s.logger.WithError(err).Fatal("start server failed")
+		if err := s.server.Serve(listen); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.reportError(err)
 		}
 	}()
 	s.logger.Info("start server success")
@@ -0,0 +1,69 @@
+package serverx
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestHttpServerStart(t *testing.T) {
+	// 占用端口，模拟端口冲突
+	listen, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listen.Close()
+	port := listen.Addr().(*net.TCPAddr).Port
+
+	server := NewHttpServer(&http.Server{}, port)
+	errs := make(chan error, 1)
+	server.BindErrorChan(errs)
+	server.BindConfig(&Config{Name: "test"})
+	if err = server.Start(context.Background()); err == nil {
+		server.Shutdown(context.Background())
+		t.Fatal("expected listen error when port is in use")
+	}
+	if server.IsRunning() {
+		t.Error("server should not be running")
+	}
+}
+
+func TestStartRollback(t *testing.T) {
+	// 获取空闲端口供第一个服务使用
+	free, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := free.Addr().(*net.TCPAddr).Port
+	_ = free.Close()
+
+	// 占用第二个服务的端口，模拟端口冲突
+	busy, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer busy.Close()
+
+	first := NewHttpServer(&http.Server{}, port)
+	second := NewHttpServer(&http.Server{}, busy.Addr().(*net.TCPAddr).Port)
+	if err = Start(context.Background(), &Config{Name: "test"}, first, second); err == nil {
+		Shutdown(context.Background(), first, second)
+		t.Fatal("expected listen error when port is in use")
+	}
+	if first.IsRunning() {
+		t.Error("started server should be shutdown when a later server fails")
+	}
+	// 第一个服务的端口应已释放，服务协程可能稍后才关闭监听
+	var listen net.Listener
+	for i := 0; i < 50; i++ {
+		if listen, err = net.Listen("tcp", fmt.Sprintf(":%d", port)); err == nil {
+			_ = listen.Close()
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("listener of the started server should be closed: %v", err)
+}